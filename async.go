@@ -0,0 +1,290 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultAsyncBufferSize is the number of entries buffered per output
+	// when AsyncConfig.BufferSize is zero.
+	defaultAsyncBufferSize = 4096
+
+	// defaultAsyncFlushInterval is the maximum time an entry stays buffered
+	// when AsyncConfig.FlushInterval is zero.
+	defaultAsyncFlushInterval = time.Second
+)
+
+// asyncEntry is an encoded entry waiting to be written.
+type asyncEntry struct {
+	level zapcore.Level
+	data  []byte
+}
+
+// asyncWriter buffers encoded entries in a bounded queue and writes them to
+// the underlying WriteSyncer from a background goroutine.
+//
+// The queue is flushed when it is half full, when the flush interval elapses,
+// on Sync and on Close. When it is full, the overflow policy decides whether
+// the caller waits or an entry is dropped; Panic and Fatal entries are never
+// dropped.
+type asyncWriter struct {
+	out       zapcore.WriteSyncer
+	policy    config.OverflowPolicy
+	dropBelow zapcore.Level
+	capacity  int
+	interval  time.Duration
+	dropped   *atomic.Uint64
+
+	mu      sync.Mutex
+	notFull *sync.Cond
+	queue   []asyncEntry
+	err     error
+	closed  bool
+
+	wake    chan struct{}
+	flushes chan chan error
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newAsyncWriter starts a writer in front of out. Dropped entries are counted
+// in dropped.
+func newAsyncWriter(out zapcore.WriteSyncer, cfg config.AsyncConfig, dropped *atomic.Uint64) (*asyncWriter, error) {
+	w := &asyncWriter{
+		out:      out,
+		policy:   cfg.Overflow,
+		capacity: cfg.BufferSize,
+		interval: cfg.FlushInterval,
+		dropped:  dropped,
+		wake:     make(chan struct{}, 1),
+		flushes:  make(chan chan error),
+		done:     make(chan struct{}),
+	}
+	if w.policy == "" {
+		w.policy = config.OverflowBlock
+	}
+	if w.policy == config.OverflowDropBelow {
+		level, err := toZapLevel(cfg.DropBelow)
+		if err != nil {
+			return nil, err
+		}
+		w.dropBelow = level
+	}
+	if w.capacity == 0 {
+		w.capacity = defaultAsyncBufferSize
+	}
+	if w.interval == 0 {
+		w.interval = defaultAsyncFlushInterval
+	}
+	w.notFull = sync.NewCond(&w.mu)
+	w.queue = make([]asyncEntry, 0, w.capacity)
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w, nil
+}
+
+// run flushes the queue until the writer is closed.
+func (w *asyncWriter) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.wake:
+			w.flush()
+		case <-ticker.C:
+			w.flush()
+		case reply := <-w.flushes:
+			w.flush()
+			reply <- w.syncOutput()
+		case <-w.done:
+			w.flush()
+			return
+		}
+	}
+}
+
+// enqueue buffers data, applying the overflow policy if the queue is full.
+// After Close, data is written directly.
+func (w *asyncWriter) enqueue(level zapcore.Level, data []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.makeRoom(level) {
+		w.dropped.Add(1)
+		return
+	}
+	if w.closed {
+		if _, err := w.out.Write(data); err != nil && w.err == nil {
+			w.err = err
+		}
+		return
+	}
+
+	w.queue = append(w.queue, asyncEntry{level: level, data: data})
+	if len(w.queue) >= (w.capacity+1)/2 {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// makeRoom waits for or makes room in the queue according to the overflow
+// policy. It reports false if the entry must be dropped. Callers must hold w.mu.
+func (w *asyncWriter) makeRoom(level zapcore.Level) bool {
+	for len(w.queue) >= w.capacity && !w.closed {
+		if level <= zapcore.ErrorLevel {
+			switch w.policy {
+			case config.OverflowDropNewest:
+				return false
+			case config.OverflowDropOldest:
+				w.queue[0] = asyncEntry{}
+				w.queue = w.queue[1:]
+				w.dropped.Add(1)
+				return true
+			case config.OverflowDropBelow:
+				if level < w.dropBelow {
+					return false
+				}
+			}
+		}
+		w.notFull.Wait()
+	}
+	return true
+}
+
+// flush writes all buffered entries to the underlying WriteSyncer. Write
+// errors are kept and reported by the next Sync.
+func (w *asyncWriter) flush() {
+	w.mu.Lock()
+	batch := w.queue
+	w.queue = make([]asyncEntry, 0, w.capacity)
+	w.notFull.Broadcast()
+	w.mu.Unlock()
+
+	var firstErr error
+	for _, e := range batch {
+		if _, err := w.out.Write(e.data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		w.mu.Lock()
+		if w.err == nil {
+			w.err = firstErr
+		}
+		w.mu.Unlock()
+	}
+}
+
+// syncOutput syncs the underlying WriteSyncer and returns it together with
+// any write error recorded since the last call.
+func (w *asyncWriter) syncOutput() error {
+	err := w.out.Sync()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		err, w.err = w.err, nil
+	}
+	return err
+}
+
+// Sync writes every entry buffered before the call and syncs the underlying
+// WriteSyncer.
+func (w *asyncWriter) Sync() error {
+	reply := make(chan error, 1)
+	select {
+	case w.flushes <- reply:
+		return <-reply
+	case <-w.done:
+		return w.syncOutput()
+	}
+}
+
+// Close flushes the buffer and stops the background goroutine. Entries
+// logged afterwards are written directly. The underlying WriteSyncer is not
+// closed.
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.notFull.Broadcast()
+	w.mu.Unlock()
+
+	close(w.done)
+	w.wg.Wait()
+	return w.syncOutput()
+}
+
+// asyncCore is a zapcore.Core encoding entries on the calling goroutine and
+// handing the bytes to an asyncWriter.
+type asyncCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out *asyncWriter
+}
+
+// newAsyncCore creates a core writing entries of enab through out.
+func newAsyncCore(enc zapcore.Encoder, out *asyncWriter, enab zapcore.LevelEnabler) zapcore.Core {
+	return &asyncCore{LevelEnabler: enab, enc: enc, out: out}
+}
+
+// Level returns the minimum enabled level of the core.
+func (c *asyncCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.LevelEnabler)
+}
+
+// With adds structured context to the core.
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &asyncCore{LevelEnabler: c.LevelEnabler, enc: c.enc.Clone(), out: c.out}
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return clone
+}
+
+// Check adds the core to ce if the entry's level is enabled.
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write encodes the entry and buffers it. Entries above ErrorLevel are
+// flushed immediately, since the process may be about to exit.
+func (c *asyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+	buf.Free()
+
+	c.out.enqueue(ent.Level, data)
+	if ent.Level > zapcore.ErrorLevel {
+		return c.out.Sync()
+	}
+	return nil
+}
+
+// Sync flushes buffered entries and syncs the output.
+func (c *asyncCore) Sync() error {
+	return c.out.Sync()
+}
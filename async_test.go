@@ -0,0 +1,267 @@
+package logger
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// gatedSink is a WriteSyncer whose writes block until release is called.
+type gatedSink struct {
+	started chan struct{}
+	gate    chan struct{}
+
+	mu     sync.Mutex
+	writes []string
+}
+
+func newGatedSink() *gatedSink {
+	return &gatedSink{started: make(chan struct{}, 1), gate: make(chan struct{})}
+}
+
+func (s *gatedSink) Write(p []byte) (int, error) {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	<-s.gate
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes = append(s.writes, string(p))
+	return len(p), nil
+}
+
+func (s *gatedSink) Sync() error { return nil }
+
+func (s *gatedSink) release() { close(s.gate) }
+
+func (s *gatedSink) written() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.writes, "")
+}
+
+// errSink is a WriteSyncer whose writes always fail.
+type errSink struct{}
+
+func (errSink) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+func (errSink) Sync() error               { return nil }
+
+// TestAsyncWriter_Overflow tests each overflow policy with a full buffer.
+func TestAsyncWriter_Overflow(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      config.AsyncConfig
+		level       zapcore.Level
+		wantBlock   bool
+		wantWritten string
+		wantDropped uint64
+	}{
+		{
+			name:        "block",
+			config:      config.AsyncConfig{Overflow: config.OverflowBlock},
+			level:       zapcore.InfoLevel,
+			wantBlock:   true,
+			wantWritten: "abcd",
+		},
+		{
+			name:        "drop newest",
+			config:      config.AsyncConfig{Overflow: config.OverflowDropNewest},
+			level:       zapcore.InfoLevel,
+			wantWritten: "abc",
+			wantDropped: 1,
+		},
+		{
+			name:        "drop oldest",
+			config:      config.AsyncConfig{Overflow: config.OverflowDropOldest},
+			level:       zapcore.InfoLevel,
+			wantWritten: "acd",
+			wantDropped: 1,
+		},
+		{
+			name:        "drop below drops lower levels",
+			config:      config.AsyncConfig{Overflow: config.OverflowDropBelow, DropBelow: config.LogLevelWarn},
+			level:       zapcore.InfoLevel,
+			wantWritten: "abc",
+			wantDropped: 1,
+		},
+		{
+			name:        "drop below blocks higher levels",
+			config:      config.AsyncConfig{Overflow: config.OverflowDropBelow, DropBelow: config.LogLevelWarn},
+			level:       zapcore.ErrorLevel,
+			wantBlock:   true,
+			wantWritten: "abcd",
+		},
+		{
+			name:        "fatal entries are never dropped",
+			config:      config.AsyncConfig{Overflow: config.OverflowDropNewest},
+			level:       zapcore.FatalLevel,
+			wantBlock:   true,
+			wantWritten: "abcd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := newGatedSink()
+			var dropped atomic.Uint64
+			tt.config.BufferSize = 2
+			tt.config.FlushInterval = time.Hour
+			w, err := newAsyncWriter(sink, tt.config, &dropped)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer w.Close()
+
+			// The first entry is picked up by the background goroutine, which
+			// then blocks in Write; the next two fill the buffer.
+			w.enqueue(zapcore.InfoLevel, []byte("a"))
+			<-sink.started
+			w.enqueue(zapcore.InfoLevel, []byte("b"))
+			w.enqueue(zapcore.InfoLevel, []byte("c"))
+
+			done := make(chan struct{})
+			go func() {
+				w.enqueue(tt.level, []byte("d"))
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				if tt.wantBlock {
+					t.Fatal("expected enqueue to block on a full buffer")
+				}
+			case <-time.After(50 * time.Millisecond):
+				if !tt.wantBlock {
+					t.Fatal("expected enqueue not to block")
+				}
+			}
+
+			sink.release()
+			<-done
+			if err := w.Sync(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := sink.written(); got != tt.wantWritten {
+				t.Errorf("written = %q, want %q", got, tt.wantWritten)
+			}
+			if got := dropped.Load(); got != tt.wantDropped {
+				t.Errorf("dropped = %d, want %d", got, tt.wantDropped)
+			}
+		})
+	}
+}
+
+// TestAsyncWriter_FlushInterval tests that entries are flushed without Sync.
+func TestAsyncWriter_FlushInterval(t *testing.T) {
+	sink := newGatedSink()
+	sink.release()
+	var dropped atomic.Uint64
+	w, err := newAsyncWriter(sink, config.AsyncConfig{BufferSize: 100, FlushInterval: 10 * time.Millisecond}, &dropped)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	w.enqueue(zapcore.InfoLevel, []byte("a"))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for sink.written() != "a" {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for flush")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestAsyncWriter_WriteError tests that write errors are reported by Sync.
+func TestAsyncWriter_WriteError(t *testing.T) {
+	var dropped atomic.Uint64
+	w, err := newAsyncWriter(errSink{}, config.AsyncConfig{}, &dropped)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	w.enqueue(zapcore.InfoLevel, []byte("a"))
+	if err := w.Sync(); err == nil {
+		t.Error("expected error but got nil")
+	}
+	if err := w.Sync(); err != nil {
+		t.Errorf("error should be reported once, got: %v", err)
+	}
+}
+
+// TestAsyncWriter_Close tests that Close drains the buffer and later writes
+// go straight to the output.
+func TestAsyncWriter_Close(t *testing.T) {
+	sink := newGatedSink()
+	sink.release()
+	var dropped atomic.Uint64
+	w, err := newAsyncWriter(sink, config.AsyncConfig{FlushInterval: time.Hour}, &dropped)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w.enqueue(zapcore.InfoLevel, []byte("a"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close should be a no-op, got: %v", err)
+	}
+	w.enqueue(zapcore.InfoLevel, []byte("b"))
+	if err := w.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := sink.written(); got != "ab" {
+		t.Errorf("written = %q, want %q", got, "ab")
+	}
+}
+
+// TestBuildLogger_Async tests that Sync drains async outputs.
+func TestBuildLogger_Async(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "async-service",
+		File:        config.FileConfig{Path: path},
+		Outputs:     []config.OutputConfig{{Type: config.OutputFile}},
+		Async:       config.AsyncConfig{Enabled: true, FlushInterval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+	defer log.Close()
+
+	child := log.With(zap.String("request_id", "abc"))
+	for i := 0; i < 10; i++ {
+		child.Info("buffered entry")
+	}
+	if err := log.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 10 {
+		t.Fatalf("expected 10 entries after Sync, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], `"request_id":"abc"`) {
+		t.Errorf("expected context field in entry: %q", lines[0])
+	}
+	if got := log.Stats().AsyncDropped; got != 0 {
+		t.Errorf("expected no dropped entries, got %d", got)
+	}
+}
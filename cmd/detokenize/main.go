@@ -0,0 +1,170 @@
+// Command detokenize re-identifies the tokens written by loggers with a
+// tokenization key (see logger.Token and config.TokenizationConfig).
+//
+// Tokens are keyed digests and cannot be reversed: detokenize tokenizes
+// candidate values, one per line, and prints each token with the value it
+// was produced from. Candidates are read from standard input, or from the
+// file given with -candidates, e.g. an export of the user IDs of a database.
+//
+// The key is read from LOG_TOKEN_KEY or LOG_TOKEN_KEY_FILE, with its ID from
+// LOG_TOKEN_KEY_ID, as loggers do. Keys of earlier rotations are given with
+// -key, once per key:
+//
+//	detokenize -key 2024-01=/run/secrets/token-key-2024-01 \
+//	    -candidates user_ids.txt tok:2024-01:4f1c... tok:2024-06:9f2c...
+//
+// Each re-identified token is printed as "<token>\t<value>". The exit status
+// is 1 if some tokens were not re-identified, and 2 on invalid usage.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	logger "github.com/gath-stack/gologger"
+	"github.com/gath-stack/gologger/config"
+)
+
+// keyFlags collects the -key flags, mapping key IDs to key files.
+type keyFlags map[string]string
+
+func (k keyFlags) String() string {
+	return fmt.Sprint(map[string]string(k))
+}
+
+func (k keyFlags) Set(value string) error {
+	id, file, ok := strings.Cut(value, "=")
+	if !ok || id == "" || file == "" {
+		return errors.New("must be ID=FILE")
+	}
+	k[id] = file
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run re-identifies the tokens given in args and returns the exit status.
+func run(args []string, getenv func(string) string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("detokenize", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	keyFiles := keyFlags{}
+	flags.Var(keyFiles, "key", "`ID=FILE` of a key of an earlier rotation (repeatable)")
+	candidatesPath := flags.String("candidates", "", "`file` of candidate values, one per line (default: standard input)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: detokenize [-key ID=FILE]... [-candidates FILE] TOKEN...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	tokens := flags.Args()
+	if len(tokens) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	keys, err := loadKeys(keyFiles, getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "detokenize: %v\n", err)
+		return 2
+	}
+
+	// Tokens wanted, keyed by the ID of their key.
+	wanted := make(map[string]map[string]bool)
+	remaining := 0
+	for _, token := range tokens {
+		keyID, ok := logger.TokenKeyID(token)
+		if !ok {
+			fmt.Fprintf(stderr, "detokenize: %q is not a token\n", token)
+			return 2
+		}
+		if _, ok := keys[keyID]; !ok {
+			fmt.Fprintf(stderr, "detokenize: no key with ID %q for %s\n", keyID, token)
+			return 2
+		}
+		if wanted[keyID] == nil {
+			wanted[keyID] = make(map[string]bool)
+		}
+		if !wanted[keyID][token] {
+			wanted[keyID][token] = true
+			remaining++
+		}
+	}
+
+	candidates := stdin
+	if *candidatesPath != "" {
+		f, err := os.Open(*candidatesPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "detokenize: %v\n", err)
+			return 2
+		}
+		defer f.Close()
+		candidates = f
+	}
+
+	found := make(map[string]bool)
+	scanner := bufio.NewScanner(candidates)
+	for remaining > 0 && scanner.Scan() {
+		value := scanner.Text()
+		for keyID, set := range wanted {
+			if token := logger.Tokenize(keys[keyID], keyID, value); set[token] && !found[token] {
+				found[token] = true
+				remaining--
+				fmt.Fprintf(stdout, "%s\t%s\n", token, value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "detokenize: reading candidates: %v\n", err)
+		return 2
+	}
+
+	status := 0
+	for _, token := range tokens {
+		if !found[token] {
+			fmt.Fprintf(stderr, "detokenize: %s: no matching candidate\n", token)
+			status = 1
+		}
+	}
+	return status
+}
+
+// loadKeys returns the keys by ID: the key configured by environment, as
+// for loggers, and the keys given by keyFiles.
+func loadKeys(keyFiles keyFlags, getenv func(string) string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	current := config.TokenizationConfig{
+		KeyID:   strings.TrimSpace(getenv("LOG_TOKEN_KEY_ID")),
+		Key:     strings.TrimSpace(getenv("LOG_TOKEN_KEY")),
+		KeyFile: strings.TrimSpace(getenv("LOG_TOKEN_KEY_FILE")),
+	}
+	configs := []config.TokenizationConfig{}
+	if current.Enabled() {
+		configs = append(configs, current)
+	}
+	for id, file := range keyFiles {
+		configs = append(configs, config.TokenizationConfig{KeyID: id, KeyFile: file})
+	}
+	if len(configs) == 0 {
+		return nil, errors.New("no key: set LOG_TOKEN_KEY or LOG_TOKEN_KEY_FILE, or use -key")
+	}
+
+	for _, cfg := range configs {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("key %q: %w", cfg.KeyID, err)
+		}
+		key, err := cfg.LoadKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", cfg.KeyID, err)
+		}
+		keys[cfg.KeyID] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logger "github.com/gath-stack/gologger"
+)
+
+const (
+	currentKey = "0123456789abcdef0123456789abcdef"
+	oldKey     = "fedcba9876543210fedcba9876543210"
+)
+
+// TestRun tests re-identifying tokens of the current and of an earlier key.
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	oldKeyFile := filepath.Join(dir, "old-key")
+	if err := os.WriteFile(oldKeyFile, []byte(oldKey), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	env := map[string]string{"LOG_TOKEN_KEY": currentKey, "LOG_TOKEN_KEY_ID": "k2"}
+	getenv := func(key string) string { return env[key] }
+
+	current := logger.Tokenize([]byte(currentKey), "k2", "user-42")
+	old := logger.Tokenize([]byte(oldKey), "k1", "user-7")
+	unknown := logger.Tokenize([]byte(currentKey), "k2", "user-99")
+	candidates := strings.NewReader("user-1\nuser-7\nuser-42\n")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStatus int
+		wantOut    string
+	}{
+		{
+			name:    "current and earlier keys",
+			args:    []string{"-key", "k1=" + oldKeyFile, current, old},
+			wantOut: old + "\tuser-7\n" + current + "\tuser-42\n",
+		},
+		{
+			name:       "no matching candidate",
+			args:       []string{unknown},
+			wantStatus: 1,
+		},
+		{
+			name:       "missing key",
+			args:       []string{old},
+			wantStatus: 2,
+		},
+		{
+			name:       "not a token",
+			args:       []string{"user-42"},
+			wantStatus: 2,
+		},
+		{
+			name:       "no tokens",
+			wantStatus: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates.Seek(0, 0)
+			var stdout, stderr bytes.Buffer
+			if status := run(tt.args, getenv, candidates, &stdout, &stderr); status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, status, stderr.String())
+			}
+			if stdout.String() != tt.wantOut {
+				t.Errorf("expected output %q, got %q", tt.wantOut, stdout.String())
+			}
+		})
+	}
+}
@@ -61,7 +61,13 @@ func mainWithErrorHandling() {
 	// Initialize logger with error handling
 	if err := logger.InitFromEnv(); err != nil {
 		// Handle error - maybe use a fallback logger or exit gracefully
-		fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
+		var verr *logger.ValidationError
+		if errors.As(err, &verr) {
+			// List every missing or invalid variable at once
+			fmt.Fprintf(os.Stderr, "invalid configuration:\n%s", verr.Table())
+		} else {
+			fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
+		}
 		os.Exit(1)
 	}
 	defer func() {
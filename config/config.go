@@ -0,0 +1,1458 @@
+// Package config manages application configuration loading and validation.
+//
+// This package centralizes all environment variable handling and validation logic,
+// providing a single source of truth for application configuration across all modules.
+//
+// Environment File Loading:
+//   - In development and test: Automatically loads .env file if present
+//   - In staging and production: Skips .env file, uses system environment variables
+//   - Other environments added with RegisterEnvironment choose through their Preset
+//   - If .env file is missing in development, falls back to system environment variables
+//
+// Key features:
+//   - Centralized environment variable loading with .env support
+//   - Automatic .env loading in non-production environments
+//   - Strict validation reporting every invalid value at once (ValidationError)
+//   - Type-safe configuration structs
+//   - Custom configuration domains (database, server, etc.) bound from struct
+//     tags and loaded alongside the logger configuration (Register, Bind)
+//   - Typed accessors for individual variables (GetInt, GetDuration, GetBool, GetList)
+//
+// Example usage:
+//
+//	type DatabaseConfig struct {
+//	    URL      string `env:"DATABASE_URL,required"`
+//	    MaxConns int    `env:"DATABASE_MAX_CONNS" default:"10"`
+//	}
+//
+//	func main() {
+//	    var db DatabaseConfig
+//	    config.MustRegister("database", &db)
+//
+//	    cfg, err := config.Load()  // Automatically loads .env if not in production
+//	    if err != nil {
+//	        log.Fatalf("failed to load configuration: %v", err)
+//	    }
+//	    // Use cfg.Logger and db
+//	}
+//
+// Production deployment:
+//   - Set APP_ENV=production in your deployment environment
+//   - Ensure all required environment variables are set by your infrastructure
+//   - Do not include .env files in production deployments
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+var (
+	// ErrMissingRequiredEnvVar is returned when a required environment variable is not set.
+	ErrMissingRequiredEnvVar = errors.New("required environment variable is not set")
+
+	// ErrInvalidValue is returned when an environment variable contains an invalid value.
+	ErrInvalidValue = errors.New("invalid configuration value")
+)
+
+// LogLevel represents the verbosity level for logging.
+type LogLevel string
+
+const (
+	// LogLevelDebug enables detailed debug and above level logging.
+	LogLevelDebug LogLevel = "DEBUG"
+	// LogLevelInfo enables informational and above level logging (default).
+	LogLevelInfo LogLevel = "INFO"
+	// LogLevelWarn enables warning and above level logging.
+	LogLevelWarn LogLevel = "WARN"
+	// LogLevelError enables error level logging only.
+	LogLevelError LogLevel = "ERROR"
+)
+
+// Validate checks if the log level is valid.
+func (l LogLevel) Validate() error {
+	switch l {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return nil
+	default:
+		return fmt.Errorf("%w: log level must be DEBUG, INFO, WARN, or ERROR, got '%s'", ErrInvalidValue, l)
+	}
+}
+
+// Environment represents the deployment environment. Each environment has a
+// Preset; more can be added with RegisterEnvironment.
+type Environment string
+
+const (
+	EnvDevelopment Environment = "development"
+	EnvTest        Environment = "test"
+	EnvStaging     Environment = "staging"
+	EnvProduction  Environment = "production"
+)
+
+// Validate checks if the environment is known.
+func (e Environment) Validate() error {
+	if _, ok := e.Preset(); ok {
+		return nil
+	}
+	return fmt.Errorf("%w: environment must be one of %s, got '%s'", ErrInvalidValue, strings.Join(environments(), ", "), e)
+}
+
+// LoggerConfig defines the configuration for the logging subsystem.
+//
+// LoggerConfig holds maps and slices, so it cannot be compared with ==;
+// use reflect.DeepEqual instead.
+type LoggerConfig struct {
+	Level       LogLevel
+	Environment Environment
+	ServiceName string
+
+	// Levels overrides Level for named loggers, keyed by logger name.
+	// An override applies to the named logger and to all of its descendants
+	// ("db" also covers "db.pool") unless they have a more specific override.
+	Levels map[string]LogLevel
+
+	// File configures an additional JSON file output. Disabled when File.Path is empty.
+	// It also provides the defaults of file outputs listed in Outputs.
+	File FileConfig
+
+	// Outputs lists the destinations of log entries. When empty, entries are
+	// written to stdout. See EffectiveOutputs.
+	Outputs []OutputConfig
+
+	// Async moves writes to outputs off the logging goroutine.
+	Async AsyncConfig
+
+	// Sampling limits repeated entries. It is also enabled by the Preset of
+	// the environment.
+	Sampling SamplingConfig
+
+	// Dedup collapses identical entries into a summary entry.
+	Dedup DedupConfig
+
+	// RateLimit limits the entries of each call site or named logger.
+	RateLimit RateLimitConfig
+
+	// Redaction rewrites sensitive values before entries are encoded.
+	Redaction RedactionConfig
+
+	// Tokenization holds the key of the tokens replacing sensitive
+	// identifiers.
+	Tokenization TokenizationConfig
+
+	// Trace names the fields carrying OpenTelemetry span context.
+	Trace TraceConfig
+
+	// OTLP exports entries as OpenTelemetry log records.
+	OTLP OTLPConfig
+}
+
+// EffectiveOutputs returns the outputs the logger writes to: Outputs, or
+// stdout when Outputs is empty, plus a file output for File if it is enabled
+// and no file output is listed. File outputs without a path inherit File.
+func (c LoggerConfig) EffectiveOutputs() []OutputConfig {
+	outputs := make([]OutputConfig, 0, len(c.Outputs)+1)
+	hasFile := false
+	for _, out := range c.Outputs {
+		if out.Type == OutputFile {
+			hasFile = true
+			if !out.File.Enabled() {
+				out.File = c.File
+			}
+		}
+		outputs = append(outputs, out)
+	}
+
+	if len(outputs) == 0 {
+		outputs = append(outputs, OutputConfig{Type: OutputStdout})
+	}
+	if c.File.Enabled() && !hasFile {
+		outputs = append(outputs, OutputConfig{Type: OutputFile, File: c.File})
+	}
+	return outputs
+}
+
+// OverflowPolicy decides what happens to an entry when the async buffer is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock waits for room in the buffer (default).
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropNewest discards the entry being logged.
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowDropOldest discards the oldest buffered entry to make room.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowDropBelow discards the entry if it is below AsyncConfig.DropBelow
+	// and waits for room otherwise.
+	OverflowDropBelow OverflowPolicy = "drop_below"
+)
+
+// Validate checks if the overflow policy is valid.
+func (p OverflowPolicy) Validate() error {
+	switch p {
+	case OverflowBlock, OverflowDropNewest, OverflowDropOldest, OverflowDropBelow:
+		return nil
+	default:
+		return fmt.Errorf("%w: overflow policy must be block, drop_newest, drop_oldest, or drop_below, got '%s'", ErrInvalidValue, p)
+	}
+}
+
+// AsyncConfig defines asynchronous, buffered writing to outputs.
+type AsyncConfig struct {
+	// Enabled turns on asynchronous writing.
+	Enabled bool
+	// BufferSize is the number of entries buffered per output. Zero uses a default.
+	BufferSize int
+	// FlushInterval is the maximum time an entry stays buffered. Zero uses a default.
+	FlushInterval time.Duration
+	// Overflow decides what happens when the buffer is full. Empty means block.
+	Overflow OverflowPolicy
+	// DropBelow is the level below which entries are dropped under OverflowDropBelow.
+	DropBelow LogLevel
+}
+
+// Validate checks if the async configuration is valid.
+func (c AsyncConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.BufferSize < 0 {
+		return atKey("buffer_size", fmt.Errorf("%w: async buffer size must not be negative, got %d", ErrInvalidValue, c.BufferSize))
+	}
+	if c.FlushInterval < 0 {
+		return atKey("flush_interval", fmt.Errorf("%w: async flush interval must not be negative, got %s", ErrInvalidValue, c.FlushInterval))
+	}
+	if c.Overflow != "" {
+		if err := c.Overflow.Validate(); err != nil {
+			return atKey("overflow", err)
+		}
+	}
+	if c.Overflow == OverflowDropBelow {
+		if c.DropBelow == "" {
+			return atKey("overflow", fmt.Errorf("%w: overflow policy drop_below requires a drop-below level", ErrInvalidValue))
+		}
+		if err := c.DropBelow.Validate(); err != nil {
+			return atKey("drop_below", err)
+		}
+	}
+	return nil
+}
+
+// Default sampling settings.
+const (
+	DefaultSamplingTick       = time.Second
+	DefaultSamplingInitial    = 100
+	DefaultSamplingThereafter = 100
+)
+
+// SamplingPolicy limits repeated entries: in each Tick, the first Initial
+// entries with the same level and message are logged, then every
+// Thereafter-th one. Zero values are inherited.
+type SamplingPolicy struct {
+	// Tick is the interval after which the counts are reset.
+	Tick time.Duration
+	// Initial is the number of entries logged per tick before sampling.
+	Initial int
+	// Thereafter logs every Thereafter-th entry past Initial.
+	Thereafter int
+}
+
+// Validate checks that the settings are not negative.
+func (p SamplingPolicy) Validate() error {
+	if p.Tick < 0 {
+		return atKey("tick", fmt.Errorf("%w: sampling tick must not be negative, got %s", ErrInvalidValue, p.Tick))
+	}
+	if p.Initial < 0 {
+		return atKey("initial", fmt.Errorf("%w: sampling initial count must not be negative, got %d", ErrInvalidValue, p.Initial))
+	}
+	if p.Thereafter < 0 {
+		return atKey("thereafter", fmt.Errorf("%w: sampling thereafter count must not be negative, got %d", ErrInvalidValue, p.Thereafter))
+	}
+	return nil
+}
+
+// SamplingConfig defines the sampling of repeated entries, per level.
+// Entries above ERROR are never sampled.
+type SamplingConfig struct {
+	// Enabled turns on sampling.
+	Enabled bool
+	// Disabled keeps sampling off in environments whose preset enables it
+	// (see Preset.Sampling). It is ignored when Enabled is set. Setting
+	// LOG_SAMPLING, or enabled in a configuration file, sets both fields.
+	Disabled bool
+	// Tick, Initial and Thereafter are the default policy. Zero values use
+	// DefaultSamplingTick, DefaultSamplingInitial and DefaultSamplingThereafter.
+	Tick       time.Duration
+	Initial    int
+	Thereafter int
+	// Levels overrides the default policy per level. Zero values of an
+	// override are inherited from the default policy.
+	Levels map[LogLevel]SamplingPolicy
+}
+
+// Policy returns the policy applied to entries at level, with inherited
+// values and defaults filled in.
+func (c SamplingConfig) Policy(level LogLevel) SamplingPolicy {
+	policy := c.Levels[level]
+	if policy.Tick == 0 {
+		policy.Tick = c.Tick
+	}
+	if policy.Initial == 0 {
+		policy.Initial = c.Initial
+	}
+	if policy.Thereafter == 0 {
+		policy.Thereafter = c.Thereafter
+	}
+
+	if policy.Tick == 0 {
+		policy.Tick = DefaultSamplingTick
+	}
+	if policy.Initial == 0 {
+		policy.Initial = DefaultSamplingInitial
+	}
+	if policy.Thereafter == 0 {
+		policy.Thereafter = DefaultSamplingThereafter
+	}
+	return policy
+}
+
+// Validate checks if the sampling configuration is valid.
+func (c SamplingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if err := (SamplingPolicy{Tick: c.Tick, Initial: c.Initial, Thereafter: c.Thereafter}).Validate(); err != nil {
+		return err
+	}
+
+	levels := make([]string, 0, len(c.Levels))
+	for level := range c.Levels {
+		levels = append(levels, string(level))
+	}
+	sort.Strings(levels)
+	for _, level := range levels {
+		if err := LogLevel(level).Validate(); err != nil {
+			return atKey("levels."+level, fmt.Errorf("sampling override: %w", err))
+		}
+		if err := c.Levels[LogLevel(level)].Validate(); err != nil {
+			return atKey("levels."+level, fmt.Errorf("sampling override for level '%s': %w", level, err))
+		}
+	}
+	return nil
+}
+
+// DefaultDedupWindow is the default deduplication window.
+const DefaultDedupWindow = 10 * time.Second
+
+// DedupConfig defines the deduplication of identical entries: those with the
+// same level, logger name, message and fields. Within Window of the first
+// one, identical entries are suppressed; when the window closes, or on Sync,
+// a single entry reports how many times the message was repeated. Entries
+// above ERROR are never suppressed.
+type DedupConfig struct {
+	// Enabled turns on deduplication.
+	Enabled bool
+	// Window is how long identical entries are suppressed after the first
+	// one. Zero uses DefaultDedupWindow.
+	Window time.Duration
+}
+
+// WithDefaults returns c with a zero window replaced by the default.
+func (c DedupConfig) WithDefaults() DedupConfig {
+	if c.Window == 0 {
+		c.Window = DefaultDedupWindow
+	}
+	return c
+}
+
+// Validate checks if the deduplication configuration is valid.
+func (c DedupConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Window < 0 {
+		return atKey("window", fmt.Errorf("%w: dedup window must not be negative, got %s", ErrInvalidValue, c.Window))
+	}
+	return nil
+}
+
+// RateLimitKey selects what gets its own rate limit.
+type RateLimitKey string
+
+const (
+	// RateLimitByCaller limits each call site (file:line) separately (default).
+	RateLimitByCaller RateLimitKey = "caller"
+	// RateLimitByLogger limits each named logger separately.
+	RateLimitByLogger RateLimitKey = "logger"
+)
+
+// Validate checks if the rate limit key is valid.
+func (k RateLimitKey) Validate() error {
+	switch k {
+	case RateLimitByCaller, RateLimitByLogger:
+		return nil
+	default:
+		return fmt.Errorf("%w: rate limit key must be caller or logger, got '%s'", ErrInvalidValue, k)
+	}
+}
+
+// RateLimit is a token bucket: entries are allowed at Rate per second on
+// average, with bursts of up to Burst entries.
+type RateLimit struct {
+	// Rate is the number of entries allowed per second. Zero disables the limit.
+	Rate float64
+	// Burst is the number of entries allowed at once. Zero uses Rate, rounded
+	// up, and at least 1.
+	Burst int
+}
+
+// Validate checks that the settings are not negative.
+func (l RateLimit) Validate() error {
+	if l.Rate < 0 {
+		return atKey("rate", fmt.Errorf("%w: rate limit must not be negative, got %g", ErrInvalidValue, l.Rate))
+	}
+	if l.Burst < 0 {
+		return atKey("burst", fmt.Errorf("%w: rate limit burst must not be negative, got %d", ErrInvalidValue, l.Burst))
+	}
+	return nil
+}
+
+// RateLimitConfig defines token-bucket rate limiting of entries. Each call
+// site or named logger, as selected by By, has its own bucket per level, so
+// that a noisy code path cannot use up the log budget of the others.
+type RateLimitConfig struct {
+	// Enabled turns on rate limiting.
+	Enabled bool
+	// By selects what gets its own buckets. Empty means per call site.
+	By RateLimitKey
+	// Rate and Burst are the limit of the levels without an override.
+	Rate  float64
+	Burst int
+	// Levels gives levels their own limit.
+	Levels map[LogLevel]RateLimit
+	// ExemptErrors lets ERROR entries and above through regardless of the
+	// limits. Otherwise they are limited as well; a limited FATAL or PANIC
+	// entry still ends the program.
+	ExemptErrors bool
+}
+
+// Limit returns the limit of entries at level, and whether the level has
+// its own limit rather than Rate and Burst.
+func (c RateLimitConfig) Limit(level LogLevel) (RateLimit, bool) {
+	if limit, ok := c.Levels[level]; ok {
+		return limit, true
+	}
+	return RateLimit{Rate: c.Rate, Burst: c.Burst}, false
+}
+
+// Validate checks if the rate limiting configuration is valid.
+func (c RateLimitConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.By != "" {
+		if err := c.By.Validate(); err != nil {
+			return atKey("by", err)
+		}
+	}
+	if err := (RateLimit{Rate: c.Rate, Burst: c.Burst}).Validate(); err != nil {
+		return err
+	}
+
+	levels := make([]string, 0, len(c.Levels))
+	for level := range c.Levels {
+		levels = append(levels, string(level))
+	}
+	sort.Strings(levels)
+	for _, level := range levels {
+		if err := LogLevel(level).Validate(); err != nil {
+			return atKey("levels."+level, fmt.Errorf("rate limit override: %w", err))
+		}
+		if err := c.Levels[LogLevel(level)].Validate(); err != nil {
+			return atKey("levels."+level, fmt.Errorf("rate limit override for level '%s': %w", level, err))
+		}
+	}
+	return nil
+}
+
+// Default field names for OpenTelemetry span context.
+const (
+	DefaultTraceIDKey    = "trace_id"
+	DefaultSpanIDKey     = "span_id"
+	DefaultTraceFlagsKey = "trace_flags"
+)
+
+// TraceConfig names the fields added to entries logged with a context that
+// carries an OpenTelemetry span. Empty names use the defaults.
+type TraceConfig struct {
+	TraceIDKey    string
+	SpanIDKey     string
+	TraceFlagsKey string
+}
+
+// WithDefaults returns c with empty names replaced by the defaults.
+func (c TraceConfig) WithDefaults() TraceConfig {
+	if c.TraceIDKey == "" {
+		c.TraceIDKey = DefaultTraceIDKey
+	}
+	if c.SpanIDKey == "" {
+		c.SpanIDKey = DefaultSpanIDKey
+	}
+	if c.TraceFlagsKey == "" {
+		c.TraceFlagsKey = DefaultTraceFlagsKey
+	}
+	return c
+}
+
+// Validate checks that the field names are distinct.
+func (c TraceConfig) Validate() error {
+	c = c.WithDefaults()
+	if c.TraceIDKey == c.SpanIDKey || c.TraceIDKey == c.TraceFlagsKey || c.SpanIDKey == c.TraceFlagsKey {
+		return fmt.Errorf("%w: trace field names must be distinct, got '%s', '%s', '%s'",
+			ErrInvalidValue, c.TraceIDKey, c.SpanIDKey, c.TraceFlagsKey)
+	}
+	return nil
+}
+
+// OTLPProtocol is the transport used to export log records.
+type OTLPProtocol string
+
+const (
+	// OTLPProtocolHTTP sends protobuf-encoded records over HTTP (default).
+	OTLPProtocolHTTP OTLPProtocol = "http/protobuf"
+	// OTLPProtocolGRPC sends records over gRPC.
+	OTLPProtocolGRPC OTLPProtocol = "grpc"
+)
+
+// Validate checks if the protocol is supported.
+func (p OTLPProtocol) Validate() error {
+	switch p {
+	case OTLPProtocolHTTP, OTLPProtocolGRPC:
+		return nil
+	default:
+		return fmt.Errorf("%w: OTLP protocol must be http/protobuf or grpc, got '%s'", ErrInvalidValue, p)
+	}
+}
+
+// OTLPConfig defines the export of entries as OpenTelemetry log records.
+//
+// Settings not covered here, such as headers, TLS, timeouts and batching, are
+// read by the exporter from the standard OTEL_EXPORTER_OTLP_* and OTEL_BLRP_*
+// environment variables.
+type OTLPConfig struct {
+	// Enabled turns on the exporter.
+	Enabled bool
+	// Protocol is the transport. Empty means http/protobuf.
+	Protocol OTLPProtocol
+	// Endpoint is the URL records are sent to, used as is, e.g.
+	// "http://localhost:4318/v1/logs" or "http://localhost:4317" for gRPC.
+	// Empty uses OTEL_EXPORTER_OTLP_LOGS_ENDPOINT, OTEL_EXPORTER_OTLP_ENDPOINT
+	// or the protocol's default.
+	Endpoint string
+	// Level is the minimum level exported (optional).
+	Level LogLevel
+}
+
+// Validate checks if the OTLP configuration is valid.
+func (c OTLPConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Protocol != "" {
+		if err := c.Protocol.Validate(); err != nil {
+			return atKey("protocol", err)
+		}
+	}
+	if c.Level != "" {
+		if err := c.Level.Validate(); err != nil {
+			return atKey("level", err)
+		}
+	}
+	return nil
+}
+
+// OutputType identifies the destination of an output.
+type OutputType string
+
+const (
+	// OutputStdout writes to the standard output.
+	OutputStdout OutputType = "stdout"
+	// OutputStderr writes to the standard error.
+	OutputStderr OutputType = "stderr"
+	// OutputFile writes to a rotating file.
+	OutputFile OutputType = "file"
+	// OutputNetwork writes to a TCP or UDP endpoint, one entry per line.
+	OutputNetwork OutputType = "network"
+)
+
+// Validate checks if the output type is valid.
+func (t OutputType) Validate() error {
+	switch t {
+	case OutputStdout, OutputStderr, OutputFile, OutputNetwork:
+		return nil
+	default:
+		return fmt.Errorf("%w: output type must be stdout, stderr, file, or network, got '%s'", ErrInvalidValue, t)
+	}
+}
+
+// Encoding selects how an output serializes entries.
+type Encoding string
+
+const (
+	// EncodingJSON writes one JSON object per entry.
+	EncodingJSON Encoding = "json"
+	// EncodingConsole writes human-readable, tab-separated entries.
+	EncodingConsole Encoding = "console"
+)
+
+// Validate checks if the encoding is valid.
+func (e Encoding) Validate() error {
+	switch e {
+	case EncodingJSON, EncodingConsole:
+		return nil
+	default:
+		return fmt.Errorf("%w: encoding must be 'json' or 'console', got '%s'", ErrInvalidValue, e)
+	}
+}
+
+// OutputConfig defines a single log destination.
+type OutputConfig struct {
+	// Type is the destination kind.
+	Type OutputType
+	// Level is the minimum level written to this output. Empty writes every
+	// entry enabled by the logger level.
+	Level LogLevel
+	// Encoding overrides the default encoding: that of the environment's
+	// Preset for stdout and stderr (console in development), JSON otherwise.
+	Encoding Encoding
+	// File configures file outputs.
+	File FileConfig
+	// Address is the endpoint of network outputs, e.g. "tcp://collector:5170"
+	// or "udp://127.0.0.1:5170".
+	Address string
+}
+
+// Validate checks if the output configuration is valid.
+func (c OutputConfig) Validate() error {
+	if err := c.Type.Validate(); err != nil {
+		return atKey("type", err)
+	}
+	if c.Level != "" {
+		if err := c.Level.Validate(); err != nil {
+			return atKey("level", err)
+		}
+	}
+	if c.Encoding != "" {
+		if err := c.Encoding.Validate(); err != nil {
+			return atKey("encoding", err)
+		}
+	}
+
+	switch c.Type {
+	case OutputFile:
+		if !c.File.Enabled() {
+			return atKey("path", fmt.Errorf("%w: file output requires a path", ErrInvalidValue))
+		}
+		return c.File.Validate()
+	case OutputNetwork:
+		if _, _, err := ParseNetworkAddress(c.Address); err != nil {
+			return atKey("address", err)
+		}
+	}
+	return nil
+}
+
+// ParseNetworkAddress splits a network output address of the form
+// "tcp://host:port" or "udp://host:port" into network and address.
+func ParseNetworkAddress(address string) (network, hostport string, err error) {
+	network, hostport, ok := strings.Cut(address, "://")
+	if !ok || hostport == "" {
+		return "", "", fmt.Errorf("%w: network output address must look like tcp://host:port or udp://host:port, got '%s'", ErrInvalidValue, address)
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		return network, hostport, nil
+	default:
+		return "", "", fmt.Errorf("%w: network output protocol must be tcp or udp, got '%s'", ErrInvalidValue, network)
+	}
+}
+
+// FileConfig defines a rotating log file output.
+type FileConfig struct {
+	// Path is the log file location. An empty path disables file output.
+	Path string
+	// MaxSizeMB rotates the file once it would exceed this size in megabytes.
+	// Zero disables size-based rotation.
+	MaxSizeMB int
+	// RotateInterval rotates the file once it has been open for this long.
+	// Zero disables time-based rotation.
+	RotateInterval time.Duration
+	// MaxAge removes rotated files older than this. Zero keeps them regardless of age.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of them.
+	MaxBackups int
+	// Compress gzips rotated files.
+	Compress bool
+}
+
+// Enabled reports whether file output is configured.
+func (c FileConfig) Enabled() bool {
+	return c.Path != ""
+}
+
+// Validate checks if the file output configuration is valid.
+func (c FileConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if strings.TrimSpace(c.Path) == "" {
+		return atKey("path", fmt.Errorf("%w: log file path cannot be blank", ErrInvalidValue))
+	}
+	if c.MaxSizeMB < 0 {
+		return atKey("max_size_mb", fmt.Errorf("%w: log file max size must not be negative, got %d", ErrInvalidValue, c.MaxSizeMB))
+	}
+	if c.RotateInterval < 0 {
+		return atKey("rotate_interval", fmt.Errorf("%w: log file rotate interval must not be negative, got %s", ErrInvalidValue, c.RotateInterval))
+	}
+	if c.MaxAge < 0 {
+		return atKey("max_age", fmt.Errorf("%w: log file max age must not be negative, got %s", ErrInvalidValue, c.MaxAge))
+	}
+	if c.MaxBackups < 0 {
+		return atKey("max_backups", fmt.Errorf("%w: log file max backups must not be negative, got %d", ErrInvalidValue, c.MaxBackups))
+	}
+	return nil
+}
+
+// Validate checks if the logger configuration is valid.
+//
+// Every invalid field is reported: the error is a *ValidationError listing
+// them by environment variable.
+func (c LoggerConfig) Validate() error {
+	return c.validate().err()
+}
+
+// validate checks every field of the configuration and identifies the
+// failures by the environment variable that sets the field.
+func (c LoggerConfig) validate() fieldErrors {
+	errs := c.validateFields()
+	for i := range errs {
+		errs[i].Key = envKey(errs[i].Key)
+	}
+	return errs
+}
+
+// validateFields checks every field of the configuration and returns the
+// failures in field order, identified by their key in configuration files
+// (e.g. "outputs[1]").
+func (c LoggerConfig) validateFields() fieldErrors {
+	var errs fieldErrors
+	check := errs.check
+
+	// Validate log level
+	check("level", c.Level.Validate())
+
+	// Validate environment
+	check("environment", c.Environment.Validate())
+
+	// Validate service name
+	if strings.TrimSpace(c.ServiceName) == "" {
+		check("service_name", fmt.Errorf("%w: service name is required and cannot be empty", ErrInvalidValue))
+	}
+
+	// Validate file output
+	check("file", c.File.Validate())
+
+	// Validate async writing
+	check("async", c.Async.Validate())
+
+	// Validate sampling
+	check("sampling", c.Sampling.Validate())
+
+	// Validate deduplication
+	check("dedup", c.Dedup.Validate())
+
+	// Validate rate limiting
+	check("rate_limit", c.RateLimit.Validate())
+
+	// Validate redaction
+	check("redaction", c.Redaction.Validate())
+	if c.Redaction.Enabled && c.Redaction.tokenizes() && !c.Tokenization.Enabled() {
+		check("redaction", fmt.Errorf("%w: the tokenize action needs a tokenization key", ErrInvalidValue))
+	}
+
+	// Validate tokenization key
+	check("tokenization", c.Tokenization.Validate())
+
+	// Validate trace field names
+	check("trace", c.Trace.Validate())
+
+	// Validate OTLP export
+	check("otlp", c.OTLP.Validate())
+
+	// Validate outputs. Those added for File or by default are covered above.
+	for i, out := range c.EffectiveOutputs()[:len(c.Outputs)] {
+		if err := out.Validate(); err != nil {
+			check(fmt.Sprintf("outputs[%d]", i), fmt.Errorf("output %d (%s): %w", i, out.Type, err))
+		}
+	}
+
+	// Validate per-logger level overrides
+	names := make([]string, 0, len(c.Levels))
+	for name := range c.Levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			check("levels", fmt.Errorf("%w: logger name in level overrides cannot be empty", ErrInvalidValue))
+			continue
+		}
+		if err := c.Levels[name].Validate(); err != nil {
+			check("levels."+name, fmt.Errorf("level override for logger '%s': %w", name, err))
+		}
+	}
+
+	return errs
+}
+
+// Config holds all application configuration.
+type Config struct {
+	Logger LoggerConfig
+
+	// Domains holds the configuration domains added with Register, keyed by
+	// name. Each value is the pointer passed to Register.
+	Domains map[string]any
+}
+
+// Validate checks if the entire configuration is valid.
+//
+// Every invalid field of every domain is reported: the error is a
+// *ValidationError.
+func (c Config) Validate() error {
+	errs := c.Logger.validate()
+
+	names := make([]string, 0, len(c.Domains))
+	for name := range c.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs = append(errs, validateDomain(name, c.Domains[name])...)
+	}
+
+	return errs.err()
+}
+
+// Load reads configuration from environment variables and validates it.
+//
+// This function automatically loads the .env file unless the preset of APP_ENV
+// disables it, as for "staging" and "production".
+// In production, environment variables must be set by the deployment environment.
+//
+// Required environment variables:
+//   - LOG_LEVEL: sets log level (DEBUG, INFO, WARN, ERROR)
+//   - APP_ENV: defines environment ("development", "test", "staging", "production",
+//     or one added with RegisterEnvironment)
+//   - APP_NAME: sets the service name field
+//
+// Optional environment variables:
+//   - LOG_LEVELS: per-logger level overrides (e.g. "db=DEBUG,http=WARN")
+//   - LOG_FILE: path of an additional JSON log file output
+//   - LOG_FILE_MAX_SIZE_MB: rotate the log file once it exceeds this size
+//   - LOG_FILE_ROTATE_INTERVAL: rotate the log file at this interval (e.g. "24h")
+//   - LOG_FILE_MAX_AGE: remove rotated log files older than this (e.g. "168h")
+//   - LOG_FILE_MAX_BACKUPS: number of rotated log files to keep
+//   - LOG_FILE_COMPRESS: gzip rotated log files ("true" or "false")
+//   - LOG_OUTPUTS: comma-separated outputs with per-output settings, e.g.
+//     "stdout?encoding=console&level=INFO,file?path=app.log,stderr?level=ERROR"
+//   - LOG_ASYNC: write to outputs asynchronously ("true" or "false")
+//   - LOG_ASYNC_BUFFER_SIZE: number of entries buffered per output
+//   - LOG_ASYNC_FLUSH_INTERVAL: maximum time an entry stays buffered (e.g. "1s")
+//   - LOG_ASYNC_OVERFLOW: block, drop_newest, drop_oldest, or drop_below
+//   - LOG_ASYNC_DROP_BELOW: level below which entries are dropped under drop_below
+//   - LOG_SAMPLING: sample repeated entries ("true" or "false"); unset, the
+//     environment's preset decides
+//   - LOG_SAMPLING_TICK: interval after which sampling counts reset (e.g. "1s")
+//   - LOG_SAMPLING_INITIAL: entries logged per tick before sampling
+//   - LOG_SAMPLING_THEREAFTER: log every Nth entry past the initial ones
+//   - LOG_SAMPLING_LEVELS: per-level overrides with the same settings, e.g.
+//     "DEBUG?initial=10&thereafter=1000,ERROR?tick=5s"
+//   - LOG_DEDUP: collapse identical entries into a summary ("true" or "false")
+//   - LOG_DEDUP_WINDOW: how long identical entries are suppressed (e.g. "10s")
+//   - LOG_RATE_LIMIT: rate limit entries ("true" or "false")
+//   - LOG_RATE_LIMIT_BY: "caller" (per call site, default) or "logger" (per named logger)
+//   - LOG_RATE_LIMIT_RATE: entries allowed per second
+//   - LOG_RATE_LIMIT_BURST: entries allowed at once
+//   - LOG_RATE_LIMIT_LEVELS: per-level limits, e.g. "DEBUG?rate=10&burst=20,INFO?rate=50"
+//   - LOG_RATE_LIMIT_EXEMPT_ERRORS: let ERROR entries and above through ("true" or "false")
+//   - LOG_REDACT: redact sensitive values ("true" or "false")
+//   - LOG_REDACT_KEYS: comma-separated field names to redact (e.g. "password,api_key")
+//   - LOG_REDACT_KEY_PATTERNS: comma-separated glob patterns of field names (e.g. "*_token")
+//   - LOG_REDACT_VALUES: comma-separated value patterns: email, credit_card, jwt or regular expressions
+//   - LOG_REDACT_ACTION: replace, mask, hash, or tokenize
+//   - LOG_TOKEN_KEY: HMAC key of tokens (at least 16 bytes)
+//   - LOG_TOKEN_KEY_FILE: file holding the HMAC key of tokens, e.g. a mounted secret
+//   - LOG_TOKEN_KEY_ID: ID of the key, included in tokens (required with a key)
+//   - LOG_TRACE_ID_KEY, LOG_SPAN_ID_KEY, LOG_TRACE_FLAGS_KEY: field names for
+//     OpenTelemetry span context (default "trace_id", "span_id", "trace_flags")
+//   - OTEL_LOGS_EXPORTER: "otlp" enables the OTLP log exporter
+//   - OTEL_EXPORTER_OTLP_LOGS_PROTOCOL, OTEL_EXPORTER_OTLP_PROTOCOL:
+//     "http/protobuf" (default) or "grpc"
+//   - LOG_OTLP_LEVEL: minimum level exported over OTLP
+//
+// Returns an error if any required variable is missing or contains invalid values.
+// The error is a *ValidationError listing every such variable, so that they
+// can all be fixed at once. The application should not start if this
+// function returns an error.
+//
+// Example:
+//
+//	cfg, err := config.Load()
+//	var verr *config.ValidationError
+//	if errors.As(err, &verr) {
+//	    fmt.Fprint(os.Stderr, verr.Table())
+//	    os.Exit(1)
+//	}
+func Load() (Config, error) {
+	return LoadOptions{}.Load()
+}
+
+// Load is like the package-level Load, reading the variables and .env files
+// selected by o.
+//
+// Example:
+//
+//	cfg, err := config.LoadOptions{Prefix: "MYSVC_"}.Load() // reads MYSVC_LOG_LEVEL, ...
+func (o LoadOptions) Load() (Config, error) {
+	// Load .env file only if not in production
+	if err := o.loadEnvFile(); err != nil {
+		return Config{}, err
+	}
+
+	return o.loadConfig(os.Getenv)
+}
+
+// loadConfig loads the logger configuration and the registered domains,
+// reporting the problems of all of them together.
+func (o LoadOptions) loadConfig(getenv getenvFunc) (Config, error) {
+	loggerCfg, errs := loadLoggerConfig(o.lookup(getenv))
+	errs = o.rename(errs)
+	domains, domainErrs := loadDomains(getenv)
+	errs = append(errs, domainErrs...)
+	if err := errs.err(); err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		Logger:  loggerCfg,
+		Domains: domains.commit(),
+	}, nil
+}
+
+// loadEnvFile loads the .env files unless the environment's preset disables them.
+//
+// The function checks the APP_ENV environment variable:
+//   - If the preset of APP_ENV disables .env files, as in "staging" and "production",
+//     they are NOT loaded (assumes env vars are set by infrastructure)
+//   - If APP_ENV is not set, is unknown, or its preset enables .env files, they are loaded
+//   - If a .env file doesn't exist in non-production, it's not an error (env vars might be set another way)
+func (o LoadOptions) loadEnvFile() error {
+	// Check if we're in production BEFORE loading .env
+	// This allows production to be set via actual environment variables
+	appEnv := os.Getenv(o.envName("APP_ENV"))
+
+	// Skip .env loading in environments whose preset disables it, such as production
+	if preset, ok := Environment(strings.ToLower(appEnv)).Preset(); ok && !preset.LoadEnvFiles {
+		return nil
+	}
+
+	// Try to load the .env files for non-production environments
+	// It's okay if a file doesn't exist - env vars might be set another way
+	for _, path := range o.envFiles() {
+		if err := godotenv.Load(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error loading %s file: %w", path, err)
+		}
+		// File doesn't exist, but that's okay - continue with system env vars
+	}
+
+	return nil
+}
+
+// MustLoad loads configuration from environment variables and panics on error.
+//
+// This is useful in main() for fail-fast behavior during application startup.
+// If any required variable is missing or invalid, the application will panic
+// with a descriptive error message.
+func MustLoad() Config {
+	cfg, err := Load()
+	if err != nil {
+		panic(fmt.Sprintf("failed to load configuration from environment: %v", err))
+	}
+	return cfg
+}
+
+// LoadFromEnvFile reads configuration from the env file at path, falling back
+// to environment variables for keys the file does not set, and validates it.
+//
+// Unlike Load, values in the file take precedence over the process
+// environment and the file is read regardless of APP_ENV. This is used to
+// reload configuration after the file changed, when the process environment
+// still holds the values loaded at startup.
+//
+// Example:
+//
+//	cfg, err := config.LoadFromEnvFile("/etc/my-service/logging.env")
+func LoadFromEnvFile(path string) (Config, error) {
+	return LoadOptions{}.LoadFromEnvFile(path)
+}
+
+// LoadFromEnvFile is like the package-level LoadFromEnvFile, reading the
+// variables selected by o.
+func (o LoadOptions) LoadFromEnvFile(path string) (Config, error) {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	return o.loadConfig(func(key string) string {
+		if value, ok := values[key]; ok {
+			return value
+		}
+		return os.Getenv(key)
+	})
+}
+
+// getenvFunc looks up a configuration variable by name, like os.Getenv.
+type getenvFunc func(key string) string
+
+// requiredEnvVars are the environment variables Load requires.
+var requiredEnvVars = []string{"LOG_LEVEL", "APP_ENV", "APP_NAME"}
+
+// loadLoggerConfig loads and validates logger-specific configuration from
+// environment, reporting every missing or invalid variable.
+func loadLoggerConfig(getenv getenvFunc) (LoggerConfig, fieldErrors) {
+	var errs fieldErrors
+	for _, key := range requiredEnvVars {
+		if getenv(key) == "" {
+			errs.check(key, ErrMissingRequiredEnvVar)
+		}
+	}
+
+	var cfg LoggerConfig
+	errs = append(errs, applyEnv(&cfg, getenv)...)
+
+	// Validate before returning. Missing variables are not reported twice.
+	for _, fe := range cfg.validate() {
+		if !errs.has(fe.Key) {
+			errs = append(errs, fe)
+		}
+	}
+
+	if len(errs) > 0 {
+		return LoggerConfig{}, errs
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides the fields of cfg whose environment variable is set,
+// leaving the others untouched. It returns the variables that could not be
+// parsed.
+func applyEnv(cfg *LoggerConfig, getenv getenvFunc) fieldErrors {
+	r := &envReader{getenv: getenv}
+
+	if value := getenv("LOG_LEVEL"); value != "" {
+		cfg.Level = LogLevel(strings.ToUpper(value))
+	}
+	if value := getenv("APP_ENV"); value != "" {
+		cfg.Environment = Environment(strings.ToLower(value))
+	}
+	if value := getenv("APP_NAME"); value != "" {
+		cfg.ServiceName = value
+	}
+
+	if value := getenv("LOG_LEVELS"); strings.TrimSpace(value) != "" {
+		levels, err := parseLevelOverrides(value)
+		if err != nil {
+			r.errs.check("LOG_LEVELS", fmt.Errorf("%w: %v", ErrInvalidValue, err))
+		} else {
+			cfg.Levels = levels
+		}
+	}
+
+	applyFileEnv(&cfg.File, r)
+
+	if value := getenv("LOG_OUTPUTS"); strings.TrimSpace(value) != "" {
+		outputs, err := parseOutputs(value)
+		if err != nil {
+			r.errs.check("LOG_OUTPUTS", fmt.Errorf("%w: %v", ErrInvalidValue, err))
+		} else {
+			cfg.Outputs = outputs
+		}
+	}
+
+	applyAsyncEnv(&cfg.Async, r)
+	applySamplingEnv(&cfg.Sampling, r)
+	r.setBool("LOG_DEDUP", &cfg.Dedup.Enabled)
+	r.setDuration("LOG_DEDUP_WINDOW", &cfg.Dedup.Window)
+	applyRateLimitEnv(&cfg.RateLimit, r)
+	applyRedactionEnv(&cfg.Redaction, r)
+	r.setString("LOG_TOKEN_KEY", &cfg.Tokenization.Key)
+	r.setString("LOG_TOKEN_KEY_FILE", &cfg.Tokenization.KeyFile)
+	r.setString("LOG_TOKEN_KEY_ID", &cfg.Tokenization.KeyID)
+
+	r.setString("LOG_TRACE_ID_KEY", &cfg.Trace.TraceIDKey)
+	r.setString("LOG_SPAN_ID_KEY", &cfg.Trace.SpanIDKey)
+	r.setString("LOG_TRACE_FLAGS_KEY", &cfg.Trace.TraceFlagsKey)
+
+	applyOTLPEnv(&cfg.OTLP, getenv)
+	return r.errs
+}
+
+// applyFileEnv overrides the file output configuration from environment.
+func applyFileEnv(cfg *FileConfig, r *envReader) {
+	if value := r.getenv("LOG_FILE"); value != "" {
+		cfg.Path = value
+	}
+	r.setInt("LOG_FILE_MAX_SIZE_MB", &cfg.MaxSizeMB)
+	r.setDuration("LOG_FILE_ROTATE_INTERVAL", &cfg.RotateInterval)
+	r.setDuration("LOG_FILE_MAX_AGE", &cfg.MaxAge)
+	r.setInt("LOG_FILE_MAX_BACKUPS", &cfg.MaxBackups)
+	r.setBool("LOG_FILE_COMPRESS", &cfg.Compress)
+}
+
+// applyAsyncEnv overrides the async writing configuration from environment.
+func applyAsyncEnv(cfg *AsyncConfig, r *envReader) {
+	if value := strings.TrimSpace(r.getenv("LOG_ASYNC_OVERFLOW")); value != "" {
+		cfg.Overflow = OverflowPolicy(strings.ToLower(value))
+	}
+	if value := strings.TrimSpace(r.getenv("LOG_ASYNC_DROP_BELOW")); value != "" {
+		cfg.DropBelow = LogLevel(strings.ToUpper(value))
+	}
+	r.setBool("LOG_ASYNC", &cfg.Enabled)
+	r.setInt("LOG_ASYNC_BUFFER_SIZE", &cfg.BufferSize)
+	r.setDuration("LOG_ASYNC_FLUSH_INTERVAL", &cfg.FlushInterval)
+}
+
+// applySamplingEnv overrides the sampling configuration from environment.
+func applySamplingEnv(cfg *SamplingConfig, r *envReader) {
+	if value := strings.TrimSpace(r.getenv("LOG_SAMPLING")); value != "" {
+		r.setBool("LOG_SAMPLING", &cfg.Enabled)
+		cfg.Disabled = !cfg.Enabled
+	}
+	r.setDuration("LOG_SAMPLING_TICK", &cfg.Tick)
+	r.setInt("LOG_SAMPLING_INITIAL", &cfg.Initial)
+	r.setInt("LOG_SAMPLING_THEREAFTER", &cfg.Thereafter)
+
+	if value := r.getenv("LOG_SAMPLING_LEVELS"); strings.TrimSpace(value) != "" {
+		levels, err := parseSamplingLevels(value)
+		if err != nil {
+			r.errs.check("LOG_SAMPLING_LEVELS", fmt.Errorf("%w: %v", ErrInvalidValue, err))
+		} else {
+			cfg.Levels = levels
+		}
+	}
+}
+
+// applyRateLimitEnv overrides the rate limiting configuration from environment.
+func applyRateLimitEnv(cfg *RateLimitConfig, r *envReader) {
+	if value := strings.TrimSpace(r.getenv("LOG_RATE_LIMIT_BY")); value != "" {
+		cfg.By = RateLimitKey(strings.ToLower(value))
+	}
+	r.setBool("LOG_RATE_LIMIT", &cfg.Enabled)
+	r.setFloat("LOG_RATE_LIMIT_RATE", &cfg.Rate)
+	r.setInt("LOG_RATE_LIMIT_BURST", &cfg.Burst)
+	r.setBool("LOG_RATE_LIMIT_EXEMPT_ERRORS", &cfg.ExemptErrors)
+
+	if value := r.getenv("LOG_RATE_LIMIT_LEVELS"); strings.TrimSpace(value) != "" {
+		levels, err := parseRateLimitLevels(value)
+		if err != nil {
+			r.errs.check("LOG_RATE_LIMIT_LEVELS", fmt.Errorf("%w: %v", ErrInvalidValue, err))
+		} else {
+			cfg.Levels = levels
+		}
+	}
+}
+
+// applyOTLPEnv overrides the OTLP exporter configuration from the standard
+// OpenTelemetry environment variables. The endpoint is left to the exporter,
+// which also reads headers, TLS and timeout settings from the environment.
+func applyOTLPEnv(cfg *OTLPConfig, getenv getenvFunc) {
+	if value := strings.TrimSpace(getenv("LOG_OTLP_LEVEL")); value != "" {
+		cfg.Level = LogLevel(strings.ToUpper(value))
+	}
+
+	if exporters := getenv("OTEL_LOGS_EXPORTER"); exporters != "" {
+		cfg.Enabled = false
+		for _, exporter := range strings.Split(exporters, ",") {
+			if strings.TrimSpace(strings.ToLower(exporter)) == "otlp" {
+				cfg.Enabled = true
+			}
+		}
+	}
+
+	protocol := getenv("OTEL_EXPORTER_OTLP_LOGS_PROTOCOL")
+	if protocol == "" {
+		protocol = getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol = strings.TrimSpace(protocol); protocol != "" {
+		cfg.Protocol = OTLPProtocol(strings.ToLower(protocol))
+	}
+}
+
+// parseOutputs parses a comma-separated list of outputs. Each output is a
+// type optionally followed by URL query settings:
+//
+//	stdout?encoding=console&level=INFO,file?path=app.log&max_size_mb=100,stderr?level=ERROR
+//
+// Supported settings are level, encoding, address (network outputs) and
+// path, max_size_mb, rotate_interval, max_age, max_backups and compress
+// (file outputs). An empty value yields no outputs.
+func parseOutputs(value string) ([]OutputConfig, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var outputs []OutputConfig
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kind, rawQuery, _ := strings.Cut(entry, "?")
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, fmt.Errorf("output '%s': %v", entry, err)
+		}
+
+		out := OutputConfig{Type: OutputType(strings.ToLower(strings.TrimSpace(kind)))}
+		for key, values := range query {
+			v := strings.TrimSpace(values[len(values)-1])
+			if err := setOutputOption(&out, key, v); err != nil {
+				return nil, fmt.Errorf("output '%s': %v", entry, err)
+			}
+		}
+		outputs = append(outputs, out)
+	}
+	return outputs, nil
+}
+
+// setOutputOption applies a single LOG_OUTPUTS setting to out.
+func setOutputOption(out *OutputConfig, key, value string) error {
+	var err error
+	switch key {
+	case "level":
+		out.Level = LogLevel(strings.ToUpper(value))
+	case "encoding":
+		out.Encoding = Encoding(strings.ToLower(value))
+	case "address":
+		out.Address = value
+	case "path":
+		out.File.Path = value
+	case "max_size_mb":
+		out.File.MaxSizeMB, err = strconv.Atoi(value)
+	case "rotate_interval":
+		out.File.RotateInterval, err = time.ParseDuration(value)
+	case "max_age":
+		out.File.MaxAge, err = time.ParseDuration(value)
+	case "max_backups":
+		out.File.MaxBackups, err = strconv.Atoi(value)
+	case "compress":
+		out.File.Compress, err = strconv.ParseBool(value)
+	default:
+		return fmt.Errorf("unknown setting '%s'", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s '%s'", key, value)
+	}
+	return nil
+}
+
+// parseSamplingLevels parses per-level sampling overrides. Each override is
+// a level followed by URL query settings:
+//
+//	DEBUG?initial=10&thereafter=1000,ERROR?tick=5s
+//
+// Supported settings are tick, initial and thereafter.
+func parseSamplingLevels(value string) (map[LogLevel]SamplingPolicy, error) {
+	levels := make(map[LogLevel]SamplingPolicy)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		level, rawQuery, _ := strings.Cut(entry, "?")
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, fmt.Errorf("sampling override '%s': %v", entry, err)
+		}
+
+		var policy SamplingPolicy
+		for key, values := range query {
+			v := strings.TrimSpace(values[len(values)-1])
+			switch key {
+			case "tick":
+				policy.Tick, err = time.ParseDuration(v)
+			case "initial":
+				policy.Initial, err = strconv.Atoi(v)
+			case "thereafter":
+				policy.Thereafter, err = strconv.Atoi(v)
+			default:
+				return nil, fmt.Errorf("sampling override '%s': unknown setting '%s'", entry, key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("sampling override '%s': invalid %s '%s'", entry, key, v)
+			}
+		}
+		levels[LogLevel(strings.ToUpper(strings.TrimSpace(level)))] = policy
+	}
+	return levels, nil
+}
+
+// parseRateLimitLevels parses per-level rate limits. Each limit is a level
+// followed by URL query settings:
+//
+//	DEBUG?rate=10&burst=20,INFO?rate=50
+//
+// Supported settings are rate and burst.
+func parseRateLimitLevels(value string) (map[LogLevel]RateLimit, error) {
+	levels := make(map[LogLevel]RateLimit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		level, rawQuery, _ := strings.Cut(entry, "?")
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit '%s': %v", entry, err)
+		}
+
+		var limit RateLimit
+		for key, values := range query {
+			v := strings.TrimSpace(values[len(values)-1])
+			switch key {
+			case "rate":
+				limit.Rate, err = strconv.ParseFloat(v, 64)
+			case "burst":
+				limit.Burst, err = strconv.Atoi(v)
+			default:
+				return nil, fmt.Errorf("rate limit '%s': unknown setting '%s'", entry, key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("rate limit '%s': invalid %s '%s'", entry, key, v)
+			}
+		}
+		levels[LogLevel(strings.ToUpper(strings.TrimSpace(level)))] = limit
+	}
+	return levels, nil
+}
+
+// envReader parses optional environment variables into configuration
+// fields, collecting the variables that cannot be parsed. Unset variables
+// leave their field unchanged.
+type envReader struct {
+	getenv getenvFunc
+	errs   fieldErrors
+}
+
+// setString sets dst to the trimmed value of key.
+func (r *envReader) setString(key string, dst *string) {
+	if value := strings.TrimSpace(r.getenv(key)); value != "" {
+		*dst = value
+	}
+}
+
+// setInt parses the integer value of key into dst.
+func (r *envReader) setInt(key string, dst *int) {
+	value := strings.TrimSpace(r.getenv(key))
+	if value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		r.errs.check(key, fmt.Errorf("%w: must be an integer, got '%s'", ErrInvalidValue, value))
+		return
+	}
+	*dst = n
+}
+
+// setFloat parses the number value of key into dst.
+func (r *envReader) setFloat(key string, dst *float64) {
+	value := strings.TrimSpace(r.getenv(key))
+	if value == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.errs.check(key, fmt.Errorf("%w: must be a number, got '%s'", ErrInvalidValue, value))
+		return
+	}
+	*dst = f
+}
+
+// setDuration parses the duration value of key into dst.
+func (r *envReader) setDuration(key string, dst *time.Duration) {
+	value := strings.TrimSpace(r.getenv(key))
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.errs.check(key, fmt.Errorf("%w: must be a duration (e.g. 30s, 24h), got '%s'", ErrInvalidValue, value))
+		return
+	}
+	*dst = d
+}
+
+// setBool parses the boolean value of key into dst.
+func (r *envReader) setBool(key string, dst *bool) {
+	value := strings.TrimSpace(r.getenv(key))
+	if value == "" {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.errs.check(key, fmt.Errorf("%w: must be true or false, got '%s'", ErrInvalidValue, value))
+		return
+	}
+	*dst = b
+}
+
+// parseLevelOverrides parses per-logger level overrides of the form
+// "name=LEVEL,name=LEVEL". Levels are normalized to upper case; an empty
+// value yields no overrides.
+func parseLevelOverrides(value string) (map[string]LogLevel, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	levels := make(map[string]LogLevel)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, level, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected name=LEVEL, got '%s'", entry)
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("missing logger name in '%s'", entry)
+		}
+		levels[name] = LogLevel(strings.ToUpper(strings.TrimSpace(level)))
+	}
+	return levels, nil
+}
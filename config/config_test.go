@@ -0,0 +1,1626 @@
+// Package config manages application configuration loading and validation.
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestLogLevel_Validate tests the validation of log levels.
+func TestLogLevel_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     LogLevel
+		wantError bool
+	}{
+		{
+			name:      "valid debug level",
+			level:     LogLevelDebug,
+			wantError: false,
+		},
+		{
+			name:      "valid info level",
+			level:     LogLevelInfo,
+			wantError: false,
+		},
+		{
+			name:      "valid warn level",
+			level:     LogLevelWarn,
+			wantError: false,
+		},
+		{
+			name:      "valid error level",
+			level:     LogLevelError,
+			wantError: false,
+		},
+		{
+			name:      "invalid log level",
+			level:     LogLevel("INVALID"),
+			wantError: true,
+		},
+		{
+			name:      "empty log level",
+			level:     LogLevel(""),
+			wantError: true,
+		},
+		{
+			name:      "lowercase log level",
+			level:     LogLevel("debug"),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.level.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantError && err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("expected ErrInvalidValue but got: %v", err)
+			}
+		})
+	}
+}
+
+// TestEnvironment_Validate tests the validation of environment values.
+func TestEnvironment_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       Environment
+		wantError bool
+	}{
+		{
+			name:      "valid development environment",
+			env:       EnvDevelopment,
+			wantError: false,
+		},
+		{
+			name:      "valid production environment",
+			env:       EnvProduction,
+			wantError: false,
+		},
+		{
+			name:      "valid staging environment",
+			env:       EnvStaging,
+			wantError: false,
+		},
+		{
+			name:      "invalid environment",
+			env:       Environment("prod"),
+			wantError: true,
+		},
+		{
+			name:      "empty environment",
+			env:       Environment(""),
+			wantError: true,
+		},
+		{
+			name:      "uppercase environment",
+			env:       Environment("PRODUCTION"),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.env.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantError && err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("expected ErrInvalidValue but got: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoggerConfig_Validate tests the validation of logger configuration.
+func TestLoggerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    LoggerConfig
+		wantError bool
+	}{
+		{
+			name: "valid configuration",
+			config: LoggerConfig{
+				Level:       LogLevelInfo,
+				Environment: EnvDevelopment,
+				ServiceName: "test-service",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid log level",
+			config: LoggerConfig{
+				Level:       LogLevel("INVALID"),
+				Environment: EnvDevelopment,
+				ServiceName: "test-service",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid environment",
+			config: LoggerConfig{
+				Level:       LogLevelInfo,
+				Environment: Environment("prod"),
+				ServiceName: "test-service",
+			},
+			wantError: true,
+		},
+		{
+			name: "empty service name",
+			config: LoggerConfig{
+				Level:       LogLevelInfo,
+				Environment: EnvDevelopment,
+				ServiceName: "",
+			},
+			wantError: true,
+		},
+		{
+			name: "whitespace only service name",
+			config: LoggerConfig{
+				Level:       LogLevelInfo,
+				Environment: EnvDevelopment,
+				ServiceName: "   ",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid level overrides",
+			config: LoggerConfig{
+				Level:       LogLevelInfo,
+				Environment: EnvDevelopment,
+				ServiceName: "test-service",
+				Levels:      map[string]LogLevel{"db": LogLevelDebug, "db.pool": LogLevelWarn},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid level override",
+			config: LoggerConfig{
+				Level:       LogLevelInfo,
+				Environment: EnvDevelopment,
+				ServiceName: "test-service",
+				Levels:      map[string]LogLevel{"db": LogLevel("TRACE")},
+			},
+			wantError: true,
+		},
+		{
+			name: "empty logger name in level overrides",
+			config: LoggerConfig{
+				Level:       LogLevelInfo,
+				Environment: EnvDevelopment,
+				ServiceName: "test-service",
+				Levels:      map[string]LogLevel{" ": LogLevelDebug},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestConfig_Validate tests the validation of the complete configuration.
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		wantError bool
+	}{
+		{
+			name: "valid configuration",
+			config: Config{
+				Logger: LoggerConfig{
+					Level:       LogLevelInfo,
+					Environment: EnvProduction,
+					ServiceName: "test-service",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid logger configuration",
+			config: Config{
+				Logger: LoggerConfig{
+					Level:       LogLevel("INVALID"),
+					Environment: EnvProduction,
+					ServiceName: "test-service",
+				},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad tests the Load function with various environment configurations.
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		envVars   map[string]string
+		wantError bool
+	}{
+		{
+			name: "valid configuration",
+			envVars: map[string]string{
+				"LOG_LEVEL": "INFO",
+				"APP_ENV":   "development",
+				"APP_NAME":  "test-service",
+			},
+			wantError: false,
+		},
+		{
+			name: "valid production configuration",
+			envVars: map[string]string{
+				"LOG_LEVEL": "ERROR",
+				"APP_ENV":   "production",
+				"APP_NAME":  "prod-service",
+			},
+			wantError: false,
+		},
+		{
+			name: "missing LOG_LEVEL",
+			envVars: map[string]string{
+				"APP_ENV":  "development",
+				"APP_NAME": "test-service",
+			},
+			wantError: true,
+		},
+		{
+			name: "missing APP_ENV",
+			envVars: map[string]string{
+				"LOG_LEVEL": "INFO",
+				"APP_NAME":  "test-service",
+			},
+			wantError: true,
+		},
+		{
+			name: "missing APP_NAME",
+			envVars: map[string]string{
+				"LOG_LEVEL": "INFO",
+				"APP_ENV":   "development",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid LOG_LEVEL",
+			envVars: map[string]string{
+				"LOG_LEVEL": "INVALID",
+				"APP_ENV":   "development",
+				"APP_NAME":  "test-service",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid APP_ENV",
+			envVars: map[string]string{
+				"LOG_LEVEL": "INFO",
+				"APP_ENV":   "prod",
+				"APP_NAME":  "test-service",
+			},
+			wantError: true,
+		},
+		{
+			name: "lowercase log level gets normalized",
+			envVars: map[string]string{
+				"LOG_LEVEL": "debug",
+				"APP_ENV":   "development",
+				"APP_NAME":  "test-service",
+			},
+			wantError: false,
+		},
+		{
+			name: "uppercase app env gets normalized",
+			envVars: map[string]string{
+				"LOG_LEVEL": "INFO",
+				"APP_ENV":   "DEVELOPMENT",
+				"APP_NAME":  "test-service",
+			},
+			wantError: false,
+		},
+		{
+			name: "valid LOG_LEVELS",
+			envVars: map[string]string{
+				"LOG_LEVEL":  "INFO",
+				"APP_ENV":    "development",
+				"APP_NAME":   "test-service",
+				"LOG_LEVELS": "db=debug, http=WARN",
+			},
+			wantError: false,
+		},
+		{
+			name: "malformed LOG_LEVELS",
+			envVars: map[string]string{
+				"LOG_LEVEL":  "INFO",
+				"APP_ENV":    "development",
+				"APP_NAME":   "test-service",
+				"LOG_LEVELS": "db",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid file output",
+			envVars: map[string]string{
+				"LOG_LEVEL":                "INFO",
+				"APP_ENV":                  "development",
+				"APP_NAME":                 "test-service",
+				"LOG_FILE":                 "/var/log/app.log",
+				"LOG_FILE_MAX_SIZE_MB":     "100",
+				"LOG_FILE_ROTATE_INTERVAL": "24h",
+				"LOG_FILE_MAX_AGE":         "168h",
+				"LOG_FILE_MAX_BACKUPS":     "7",
+				"LOG_FILE_COMPRESS":        "true",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid LOG_FILE_MAX_SIZE_MB",
+			envVars: map[string]string{
+				"LOG_LEVEL":            "INFO",
+				"APP_ENV":              "development",
+				"APP_NAME":             "test-service",
+				"LOG_FILE":             "/var/log/app.log",
+				"LOG_FILE_MAX_SIZE_MB": "lots",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid LOG_FILE_ROTATE_INTERVAL",
+			envVars: map[string]string{
+				"LOG_LEVEL":                "INFO",
+				"APP_ENV":                  "development",
+				"APP_NAME":                 "test-service",
+				"LOG_FILE":                 "/var/log/app.log",
+				"LOG_FILE_ROTATE_INTERVAL": "daily",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid LOG_FILE_COMPRESS",
+			envVars: map[string]string{
+				"LOG_LEVEL":         "INFO",
+				"APP_ENV":           "development",
+				"APP_NAME":          "test-service",
+				"LOG_FILE":          "/var/log/app.log",
+				"LOG_FILE_COMPRESS": "maybe",
+			},
+			wantError: true,
+		},
+		{
+			name: "negative LOG_FILE_MAX_BACKUPS",
+			envVars: map[string]string{
+				"LOG_LEVEL":            "INFO",
+				"APP_ENV":              "development",
+				"APP_NAME":             "test-service",
+				"LOG_FILE":             "/var/log/app.log",
+				"LOG_FILE_MAX_BACKUPS": "-1",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid LOG_OUTPUTS",
+			envVars: map[string]string{
+				"LOG_LEVEL":   "INFO",
+				"APP_ENV":     "development",
+				"APP_NAME":    "test-service",
+				"LOG_OUTPUTS": "stdout?encoding=console&level=info,file?path=/var/log/app.log,stderr?level=ERROR",
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown output type in LOG_OUTPUTS",
+			envVars: map[string]string{
+				"LOG_LEVEL":   "INFO",
+				"APP_ENV":     "development",
+				"APP_NAME":    "test-service",
+				"LOG_OUTPUTS": "syslog",
+			},
+			wantError: true,
+		},
+		{
+			name: "unknown setting in LOG_OUTPUTS",
+			envVars: map[string]string{
+				"LOG_LEVEL":   "INFO",
+				"APP_ENV":     "development",
+				"APP_NAME":    "test-service",
+				"LOG_OUTPUTS": "stdout?colour=true",
+			},
+			wantError: true,
+		},
+		{
+			name: "valid async configuration",
+			envVars: map[string]string{
+				"LOG_LEVEL":          "INFO",
+				"APP_ENV":            "production",
+				"APP_NAME":           "test-service",
+				"LOG_ASYNC":          "true",
+				"LOG_ASYNC_OVERFLOW": "drop_oldest",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid LOG_ASYNC_BUFFER_SIZE",
+			envVars: map[string]string{
+				"LOG_LEVEL":             "INFO",
+				"APP_ENV":               "production",
+				"APP_NAME":              "test-service",
+				"LOG_ASYNC":             "true",
+				"LOG_ASYNC_BUFFER_SIZE": "lots",
+			},
+			wantError: true,
+		},
+		{
+			name: "drop_below without LOG_ASYNC_DROP_BELOW",
+			envVars: map[string]string{
+				"LOG_LEVEL":          "INFO",
+				"APP_ENV":            "production",
+				"APP_NAME":           "test-service",
+				"LOG_ASYNC":          "true",
+				"LOG_ASYNC_OVERFLOW": "drop_below",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid level in LOG_LEVELS",
+			envVars: map[string]string{
+				"LOG_LEVEL":  "INFO",
+				"APP_ENV":    "development",
+				"APP_NAME":   "test-service",
+				"LOG_LEVELS": "db=TRACE",
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clear environment before each test
+			clearEnv()
+
+			// Set environment variables
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			// Run Load
+			cfg, err := Load()
+
+			// Check error expectation
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// Verify configuration values when no error expected
+			if !tt.wantError && err == nil {
+				if cfg.Logger.ServiceName != tt.envVars["APP_NAME"] {
+					t.Errorf("expected service name %q, got %q", tt.envVars["APP_NAME"], cfg.Logger.ServiceName)
+				}
+			}
+
+			// Clean up
+			clearEnv()
+		})
+	}
+}
+
+// TestLoad_FileConfig tests that file output settings are loaded from environment.
+func TestLoad_FileConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "development")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_FILE", "/var/log/app.log")
+	os.Setenv("LOG_FILE_MAX_SIZE_MB", "100")
+	os.Setenv("LOG_FILE_ROTATE_INTERVAL", "24h")
+	os.Setenv("LOG_FILE_MAX_AGE", "168h")
+	os.Setenv("LOG_FILE_MAX_BACKUPS", "7")
+	os.Setenv("LOG_FILE_COMPRESS", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := FileConfig{
+		Path:           "/var/log/app.log",
+		MaxSizeMB:      100,
+		RotateInterval: 24 * time.Hour,
+		MaxAge:         168 * time.Hour,
+		MaxBackups:     7,
+		Compress:       true,
+	}
+	if cfg.Logger.File != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.Logger.File)
+	}
+}
+
+// TestFileConfig_Validate tests the validation of file output configuration.
+func TestFileConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    FileConfig
+		wantError bool
+	}{
+		{
+			name:      "disabled",
+			config:    FileConfig{},
+			wantError: false,
+		},
+		{
+			name:      "disabled ignores other settings",
+			config:    FileConfig{MaxSizeMB: -1},
+			wantError: false,
+		},
+		{
+			name:      "valid",
+			config:    FileConfig{Path: "app.log", MaxSizeMB: 10, MaxBackups: 3},
+			wantError: false,
+		},
+		{
+			name:      "blank path",
+			config:    FileConfig{Path: "   "},
+			wantError: true,
+		},
+		{
+			name:      "negative max size",
+			config:    FileConfig{Path: "app.log", MaxSizeMB: -1},
+			wantError: true,
+		},
+		{
+			name:      "negative rotate interval",
+			config:    FileConfig{Path: "app.log", RotateInterval: -time.Hour},
+			wantError: true,
+		},
+		{
+			name:      "negative max age",
+			config:    FileConfig{Path: "app.log", MaxAge: -time.Hour},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantError && err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("expected ErrInvalidValue but got: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad_AsyncConfig tests that async settings are loaded from environment.
+func TestLoad_AsyncConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_ASYNC", "true")
+	os.Setenv("LOG_ASYNC_BUFFER_SIZE", "512")
+	os.Setenv("LOG_ASYNC_FLUSH_INTERVAL", "250ms")
+	os.Setenv("LOG_ASYNC_OVERFLOW", "DROP_BELOW")
+	os.Setenv("LOG_ASYNC_DROP_BELOW", "warn")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := AsyncConfig{
+		Enabled:       true,
+		BufferSize:    512,
+		FlushInterval: 250 * time.Millisecond,
+		Overflow:      OverflowDropBelow,
+		DropBelow:     LogLevelWarn,
+	}
+	if cfg.Logger.Async != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.Logger.Async)
+	}
+}
+
+// TestAsyncConfig_Validate tests the validation of async configuration.
+func TestAsyncConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    AsyncConfig
+		wantError bool
+	}{
+		{name: "disabled ignores other settings", config: AsyncConfig{BufferSize: -1}},
+		{name: "defaults", config: AsyncConfig{Enabled: true}},
+		{name: "drop newest", config: AsyncConfig{Enabled: true, BufferSize: 10, Overflow: OverflowDropNewest}},
+		{name: "drop below", config: AsyncConfig{Enabled: true, Overflow: OverflowDropBelow, DropBelow: LogLevelWarn}},
+		{name: "negative buffer size", config: AsyncConfig{Enabled: true, BufferSize: -1}, wantError: true},
+		{name: "negative flush interval", config: AsyncConfig{Enabled: true, FlushInterval: -time.Second}, wantError: true},
+		{name: "unknown overflow policy", config: AsyncConfig{Enabled: true, Overflow: "drop_all"}, wantError: true},
+		{name: "drop below without level", config: AsyncConfig{Enabled: true, Overflow: OverflowDropBelow}, wantError: true},
+		{name: "drop below with invalid level", config: AsyncConfig{Enabled: true, Overflow: OverflowDropBelow, DropBelow: "TRACE"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad_SamplingConfig tests that sampling settings are loaded from environment.
+func TestLoad_SamplingConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_SAMPLING", "true")
+	os.Setenv("LOG_SAMPLING_TICK", "2s")
+	os.Setenv("LOG_SAMPLING_INITIAL", "50")
+	os.Setenv("LOG_SAMPLING_LEVELS", "debug?initial=10&thereafter=1000, ERROR?tick=5s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := SamplingConfig{
+		Enabled: true,
+		Tick:    2 * time.Second,
+		Initial: 50,
+		Levels: map[LogLevel]SamplingPolicy{
+			LogLevelDebug: {Initial: 10, Thereafter: 1000},
+			LogLevelError: {Tick: 5 * time.Second},
+		},
+	}
+	if !reflect.DeepEqual(cfg.Logger.Sampling, want) {
+		t.Errorf("expected %+v, got %+v", want, cfg.Logger.Sampling)
+	}
+
+	t.Run("reports invalid settings", func(t *testing.T) {
+		os.Setenv("LOG_SAMPLING_INITIAL", "some")
+		os.Setenv("LOG_SAMPLING_LEVELS", "DEBUG?every=10")
+
+		_, err := Load()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		var keys []string
+		for _, fe := range verr.Errors {
+			keys = append(keys, fe.Key)
+		}
+		if want := []string{"LOG_SAMPLING_INITIAL", "LOG_SAMPLING_LEVELS"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("expected %v, got %v", want, keys)
+		}
+	})
+
+	t.Run("explicitly disabled", func(t *testing.T) {
+		os.Unsetenv("LOG_SAMPLING_INITIAL")
+		os.Unsetenv("LOG_SAMPLING_LEVELS")
+
+		os.Setenv("LOG_SAMPLING", "false")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Logger.Sampling.Enabled || !cfg.Logger.Sampling.Disabled {
+			t.Errorf("expected sampling to be disabled, got %+v", cfg.Logger.Sampling)
+		}
+
+		os.Unsetenv("LOG_SAMPLING")
+		cfg, err = Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Logger.Sampling.Enabled || cfg.Logger.Sampling.Disabled {
+			t.Errorf("expected sampling to be left to the preset, got %+v", cfg.Logger.Sampling)
+		}
+	})
+}
+
+// TestSamplingConfig_Policy tests that level policies inherit the default policy.
+func TestSamplingConfig_Policy(t *testing.T) {
+	cfg := SamplingConfig{
+		Enabled: true,
+		Initial: 50,
+		Levels: map[LogLevel]SamplingPolicy{
+			LogLevelDebug: {Initial: 10, Thereafter: 1000},
+			LogLevelError: {Tick: 5 * time.Second},
+		},
+	}
+
+	tests := []struct {
+		level LogLevel
+		want  SamplingPolicy
+	}{
+		{level: LogLevelDebug, want: SamplingPolicy{Tick: time.Second, Initial: 10, Thereafter: 1000}},
+		{level: LogLevelInfo, want: SamplingPolicy{Tick: time.Second, Initial: 50, Thereafter: 100}},
+		{level: LogLevelError, want: SamplingPolicy{Tick: 5 * time.Second, Initial: 50, Thereafter: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			if got := cfg.Policy(tt.level); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSamplingConfig_Validate tests the validation of sampling configuration.
+func TestSamplingConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    SamplingConfig
+		wantError bool
+	}{
+		{name: "disabled ignores other settings", config: SamplingConfig{Initial: -1}},
+		{name: "defaults", config: SamplingConfig{Enabled: true}},
+		{name: "level override", config: SamplingConfig{Enabled: true, Levels: map[LogLevel]SamplingPolicy{LogLevelDebug: {Thereafter: 1000}}}},
+		{name: "negative tick", config: SamplingConfig{Enabled: true, Tick: -time.Second}, wantError: true},
+		{name: "negative initial", config: SamplingConfig{Enabled: true, Initial: -1}, wantError: true},
+		{name: "negative thereafter", config: SamplingConfig{Enabled: true, Thereafter: -1}, wantError: true},
+		{name: "invalid override level", config: SamplingConfig{Enabled: true, Levels: map[LogLevel]SamplingPolicy{"TRACE": {}}}, wantError: true},
+		{name: "invalid override", config: SamplingConfig{Enabled: true, Levels: map[LogLevel]SamplingPolicy{LogLevelWarn: {Initial: -1}}}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad_DedupConfig tests that deduplication settings are loaded from environment.
+func TestLoad_DedupConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_DEDUP", "true")
+	os.Setenv("LOG_DEDUP_WINDOW", "30s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (DedupConfig{Enabled: true, Window: 30 * time.Second}); cfg.Logger.Dedup != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.Logger.Dedup)
+	}
+}
+
+// TestDedupConfig_Validate tests the validation of deduplication configuration.
+func TestDedupConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    DedupConfig
+		wantError bool
+	}{
+		{name: "disabled ignores other settings", config: DedupConfig{Window: -time.Second}},
+		{name: "default window", config: DedupConfig{Enabled: true}},
+		{name: "window", config: DedupConfig{Enabled: true, Window: time.Minute}},
+		{name: "negative window", config: DedupConfig{Enabled: true, Window: -time.Second}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad_RateLimitConfig tests that rate limiting settings are loaded from environment.
+func TestLoad_RateLimitConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_RATE_LIMIT", "true")
+	os.Setenv("LOG_RATE_LIMIT_BY", "Logger")
+	os.Setenv("LOG_RATE_LIMIT_RATE", "0.5")
+	os.Setenv("LOG_RATE_LIMIT_BURST", "10")
+	os.Setenv("LOG_RATE_LIMIT_LEVELS", "debug?rate=10&burst=20")
+	os.Setenv("LOG_RATE_LIMIT_EXEMPT_ERRORS", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := RateLimitConfig{
+		Enabled:      true,
+		By:           RateLimitByLogger,
+		Rate:         0.5,
+		Burst:        10,
+		Levels:       map[LogLevel]RateLimit{LogLevelDebug: {Rate: 10, Burst: 20}},
+		ExemptErrors: true,
+	}
+	if !reflect.DeepEqual(cfg.Logger.RateLimit, want) {
+		t.Errorf("expected %+v, got %+v", want, cfg.Logger.RateLimit)
+	}
+
+	t.Run("reports invalid settings", func(t *testing.T) {
+		os.Setenv("LOG_RATE_LIMIT_RATE", "fast")
+		os.Setenv("LOG_RATE_LIMIT_BY", "host")
+
+		_, err := Load()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		var keys []string
+		for _, fe := range verr.Errors {
+			keys = append(keys, fe.Key)
+		}
+		if want := []string{"LOG_RATE_LIMIT_RATE", "LOG_RATE_LIMIT*"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("expected %v, got %v", want, keys)
+		}
+	})
+}
+
+// TestRateLimitConfig_Validate tests the validation of rate limiting configuration.
+func TestRateLimitConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    RateLimitConfig
+		wantError bool
+	}{
+		{name: "disabled ignores other settings", config: RateLimitConfig{Rate: -1}},
+		{name: "per caller", config: RateLimitConfig{Enabled: true, Rate: 100}},
+		{name: "per logger with level limit", config: RateLimitConfig{Enabled: true, By: RateLimitByLogger, Levels: map[LogLevel]RateLimit{LogLevelDebug: {Rate: 1}}}},
+		{name: "unknown key", config: RateLimitConfig{Enabled: true, By: "host"}, wantError: true},
+		{name: "negative rate", config: RateLimitConfig{Enabled: true, Rate: -1}, wantError: true},
+		{name: "negative burst", config: RateLimitConfig{Enabled: true, Burst: -1}, wantError: true},
+		{name: "invalid level", config: RateLimitConfig{Enabled: true, Levels: map[LogLevel]RateLimit{"FATAL": {Rate: 1}}}, wantError: true},
+		{name: "invalid level limit", config: RateLimitConfig{Enabled: true, Levels: map[LogLevel]RateLimit{LogLevelInfo: {Burst: -1}}}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestTraceConfig_Validate tests the validation of trace field names.
+func TestTraceConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    TraceConfig
+		wantError bool
+	}{
+		{name: "defaults", config: TraceConfig{}},
+		{name: "custom names", config: TraceConfig{TraceIDKey: "dd.trace_id", SpanIDKey: "dd.span_id"}},
+		{name: "duplicate names", config: TraceConfig{TraceIDKey: "id", SpanIDKey: "id"}, wantError: true},
+		{name: "custom name equal to a default", config: TraceConfig{TraceFlagsKey: "span_id"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad_TraceConfig tests that trace field names are loaded from environment.
+func TestLoad_TraceConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_TRACE_ID_KEY", "dd.trace_id")
+	os.Setenv("LOG_SPAN_ID_KEY", "dd.span_id")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := TraceConfig{TraceIDKey: "dd.trace_id", SpanIDKey: "dd.span_id"}
+	if cfg.Logger.Trace != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.Logger.Trace)
+	}
+}
+
+// TestOTLPConfig_Validate tests the validation of OTLP configuration.
+func TestOTLPConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    OTLPConfig
+		wantError bool
+	}{
+		{name: "disabled ignores other settings", config: OTLPConfig{Protocol: "http/json"}},
+		{name: "defaults", config: OTLPConfig{Enabled: true}},
+		{name: "grpc with level", config: OTLPConfig{Enabled: true, Protocol: OTLPProtocolGRPC, Level: LogLevelWarn}},
+		{name: "unsupported protocol", config: OTLPConfig{Enabled: true, Protocol: "http/json"}, wantError: true},
+		{name: "invalid level", config: OTLPConfig{Enabled: true, Level: "TRACE"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad_OTLPConfig tests that the OTLP exporter is configured from the
+// standard OpenTelemetry environment variables.
+func TestLoad_OTLPConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		want    OTLPConfig
+	}{
+		{
+			name: "disabled by default",
+			want: OTLPConfig{},
+		},
+		{
+			name:    "enabled by OTEL_LOGS_EXPORTER",
+			envVars: map[string]string{"OTEL_LOGS_EXPORTER": "console,otlp"},
+			want:    OTLPConfig{Enabled: true},
+		},
+		{
+			name: "signal-specific protocol wins",
+			envVars: map[string]string{
+				"OTEL_LOGS_EXPORTER":               "otlp",
+				"OTEL_EXPORTER_OTLP_PROTOCOL":      "http/protobuf",
+				"OTEL_EXPORTER_OTLP_LOGS_PROTOCOL": "grpc",
+				"LOG_OTLP_LEVEL":                   "warn",
+			},
+			want: OTLPConfig{Enabled: true, Protocol: OTLPProtocolGRPC, Level: LogLevelWarn},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			defer clearEnv()
+
+			os.Setenv("LOG_LEVEL", "INFO")
+			os.Setenv("APP_ENV", "production")
+			os.Setenv("APP_NAME", "test-service")
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Logger.OTLP != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, cfg.Logger.OTLP)
+			}
+		})
+	}
+}
+
+// TestParseOutputs tests parsing of the LOG_OUTPUTS format.
+func TestParseOutputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      []OutputConfig
+		wantError bool
+	}{
+		{
+			name:  "empty value",
+			value: "",
+			want:  nil,
+		},
+		{
+			name:  "types only",
+			value: "stdout, STDERR",
+			want: []OutputConfig{
+				{Type: OutputStdout},
+				{Type: OutputStderr},
+			},
+		},
+		{
+			name:  "settings",
+			value: "stdout?encoding=Console&level=info,file?path=/var/log/app.log&level=DEBUG&max_size_mb=10&rotate_interval=1h&max_age=24h&max_backups=3&compress=true,network?address=udp://127.0.0.1:5170",
+			want: []OutputConfig{
+				{Type: OutputStdout, Encoding: EncodingConsole, Level: LogLevelInfo},
+				{Type: OutputFile, Level: LogLevelDebug, File: FileConfig{
+					Path:           "/var/log/app.log",
+					MaxSizeMB:      10,
+					RotateInterval: time.Hour,
+					MaxAge:         24 * time.Hour,
+					MaxBackups:     3,
+					Compress:       true,
+				}},
+				{Type: OutputNetwork, Address: "udp://127.0.0.1:5170"},
+			},
+		},
+		{
+			name:      "unknown setting",
+			value:     "stdout?color=true",
+			wantError: true,
+		},
+		{
+			name:      "invalid number",
+			value:     "file?max_size_mb=big",
+			wantError: true,
+		},
+		{
+			name:      "malformed query",
+			value:     "stdout?level=%zz",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOutputs(tt.value)
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("output %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+// TestLoggerConfig_EffectiveOutputs tests the default and inherited outputs.
+func TestLoggerConfig_EffectiveOutputs(t *testing.T) {
+	file := FileConfig{Path: "/var/log/app.log", MaxBackups: 3}
+
+	tests := []struct {
+		name   string
+		config LoggerConfig
+		want   []OutputConfig
+	}{
+		{
+			name:   "defaults to stdout",
+			config: LoggerConfig{},
+			want:   []OutputConfig{{Type: OutputStdout}},
+		},
+		{
+			name:   "file adds a file output",
+			config: LoggerConfig{File: file},
+			want:   []OutputConfig{{Type: OutputStdout}, {Type: OutputFile, File: file}},
+		},
+		{
+			name:   "explicit outputs replace stdout",
+			config: LoggerConfig{Outputs: []OutputConfig{{Type: OutputStderr}}},
+			want:   []OutputConfig{{Type: OutputStderr}},
+		},
+		{
+			name: "file outputs without path inherit file",
+			config: LoggerConfig{
+				File:    file,
+				Outputs: []OutputConfig{{Type: OutputFile, Level: LogLevelDebug}},
+			},
+			want: []OutputConfig{{Type: OutputFile, Level: LogLevelDebug, File: file}},
+		},
+		{
+			name: "file outputs with path keep it",
+			config: LoggerConfig{
+				File:    file,
+				Outputs: []OutputConfig{{Type: OutputFile, File: FileConfig{Path: "other.log"}}},
+			},
+			want: []OutputConfig{{Type: OutputFile, File: FileConfig{Path: "other.log"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config.EffectiveOutputs()
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("output %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+// TestOutputConfig_Validate tests the validation of output configuration.
+func TestOutputConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    OutputConfig
+		wantError bool
+	}{
+		{name: "stdout", config: OutputConfig{Type: OutputStdout}},
+		{name: "stderr with level and encoding", config: OutputConfig{Type: OutputStderr, Level: LogLevelError, Encoding: EncodingJSON}},
+		{name: "file", config: OutputConfig{Type: OutputFile, File: FileConfig{Path: "app.log"}}},
+		{name: "tcp network", config: OutputConfig{Type: OutputNetwork, Address: "tcp://collector:5170"}},
+		{name: "udp network", config: OutputConfig{Type: OutputNetwork, Address: "udp://127.0.0.1:5170"}},
+		{name: "unknown type", config: OutputConfig{Type: "syslog"}, wantError: true},
+		{name: "invalid level", config: OutputConfig{Type: OutputStdout, Level: "TRACE"}, wantError: true},
+		{name: "invalid encoding", config: OutputConfig{Type: OutputStdout, Encoding: "xml"}, wantError: true},
+		{name: "file without path", config: OutputConfig{Type: OutputFile}, wantError: true},
+		{name: "network without address", config: OutputConfig{Type: OutputNetwork}, wantError: true},
+		{name: "network with unsupported protocol", config: OutputConfig{Type: OutputNetwork, Address: "http://collector"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantError && err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("expected ErrInvalidValue but got: %v", err)
+			}
+		})
+	}
+}
+
+// TestParseLevelOverrides tests parsing of per-logger level overrides.
+func TestParseLevelOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      map[string]LogLevel
+		wantError bool
+	}{
+		{
+			name:  "empty value",
+			value: "",
+			want:  nil,
+		},
+		{
+			name:  "single override",
+			value: "db=DEBUG",
+			want:  map[string]LogLevel{"db": LogLevelDebug},
+		},
+		{
+			name:  "multiple overrides with whitespace and lowercase",
+			value: " db = debug , db.pool=warn,, http=ERROR ",
+			want: map[string]LogLevel{
+				"db":      LogLevelDebug,
+				"db.pool": LogLevelWarn,
+				"http":    LogLevelError,
+			},
+		},
+		{
+			name:      "missing separator",
+			value:     "db",
+			wantError: true,
+		},
+		{
+			name:      "missing name",
+			value:     "=DEBUG",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLevelOverrides(tt.value)
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for name, level := range tt.want {
+				if got[name] != level {
+					t.Errorf("expected %s=%s, got %s", name, level, got[name])
+				}
+			}
+		})
+	}
+}
+
+// TestMustLoad tests the MustLoad function.
+func TestMustLoad(t *testing.T) {
+	t.Run("panics on missing environment variables", func(t *testing.T) {
+		clearEnv()
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic but got none")
+			}
+		}()
+
+		MustLoad()
+	})
+
+	t.Run("returns config on valid environment", func(t *testing.T) {
+		clearEnv()
+		os.Setenv("LOG_LEVEL", "INFO")
+		os.Setenv("APP_ENV", "development")
+		os.Setenv("APP_NAME", "test-service")
+
+		defer clearEnv()
+
+		cfg := MustLoad()
+		if cfg.Logger.ServiceName != "test-service" {
+			t.Errorf("expected service name %q, got %q", "test-service", cfg.Logger.ServiceName)
+		}
+	})
+}
+
+// TestGetEnv tests the GetEnv function.
+func TestGetEnv(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue string
+		envValue     string
+		setEnv       bool
+		want         string
+	}{
+		{
+			name:         "returns environment value when set",
+			key:          "TEST_VAR",
+			defaultValue: "default",
+			envValue:     "custom",
+			setEnv:       true,
+			want:         "custom",
+		},
+		{
+			name:         "returns default value when not set",
+			key:          "TEST_VAR",
+			defaultValue: "default",
+			envValue:     "",
+			setEnv:       false,
+			want:         "default",
+		},
+		{
+			name:         "returns empty string default when not set",
+			key:          "TEST_VAR",
+			defaultValue: "",
+			envValue:     "",
+			setEnv:       false,
+			want:         "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+
+			if tt.setEnv {
+				os.Setenv(tt.key, tt.envValue)
+			}
+
+			got := GetEnv(tt.key, tt.defaultValue)
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+
+			clearEnv()
+		})
+	}
+}
+
+// TestRequireEnv tests the RequireEnv function.
+func TestRequireEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		setEnv    bool
+		wantError bool
+		wantValue string
+	}{
+		{
+			name:      "returns value when set",
+			key:       "REQUIRED_VAR",
+			value:     "test-value",
+			setEnv:    true,
+			wantError: false,
+			wantValue: "test-value",
+		},
+		{
+			name:      "returns error when not set",
+			key:       "REQUIRED_VAR",
+			value:     "",
+			setEnv:    false,
+			wantError: true,
+			wantValue: "",
+		},
+		{
+			name:      "returns error when set to empty string",
+			key:       "REQUIRED_VAR",
+			value:     "",
+			setEnv:    true,
+			wantError: true,
+			wantValue: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+
+			if tt.setEnv {
+				os.Setenv(tt.key, tt.value)
+			}
+
+			got, err := RequireEnv(tt.key)
+
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantError && err != nil && !errors.Is(err, ErrMissingRequiredEnvVar) {
+				t.Errorf("expected ErrMissingRequiredEnvVar but got: %v", err)
+			}
+			if got != tt.wantValue {
+				t.Errorf("expected %q, got %q", tt.wantValue, got)
+			}
+
+			clearEnv()
+		})
+	}
+}
+
+// TestLoadEnvFile tests the loadEnvFile function behavior.
+func TestLoadEnvFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		appEnv    string
+		setAppEnv bool
+		wantError bool
+	}{
+		{
+			name:      "skips loading in production",
+			appEnv:    "production",
+			setAppEnv: true,
+			wantError: false,
+		},
+		{
+			name:      "skips loading in PRODUCTION (uppercase)",
+			appEnv:    "PRODUCTION",
+			setAppEnv: true,
+			wantError: false,
+		},
+		{
+			name:      "attempts loading in development",
+			appEnv:    "development",
+			setAppEnv: true,
+			wantError: false, // No error even if .env doesn't exist
+		},
+		{
+			name:      "attempts loading when APP_ENV not set",
+			appEnv:    "",
+			setAppEnv: false,
+			wantError: false, // No error even if .env doesn't exist
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+
+			if tt.setAppEnv {
+				os.Setenv("APP_ENV", tt.appEnv)
+			}
+
+			err := LoadOptions{}.loadEnvFile()
+
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			clearEnv()
+		})
+	}
+}
+
+// TestLoadFromEnvFile tests loading configuration from an env file.
+func TestLoadFromEnvFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantLevel LogLevel
+		wantName  string
+		wantError error
+	}{
+		{
+			name:      "file overrides environment",
+			content:   "LOG_LEVEL=debug\nAPP_ENV=production\n",
+			wantLevel: LogLevelDebug,
+			wantName:  "env-service",
+		},
+		{
+			name:      "invalid value",
+			content:   "LOG_LEVEL=LOUD\n",
+			wantError: ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			defer clearEnv()
+			os.Setenv("LOG_LEVEL", "INFO")
+			os.Setenv("APP_ENV", "development")
+			os.Setenv("APP_NAME", "env-service")
+
+			path := t.TempDir() + "/logging.env"
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write env file: %v", err)
+			}
+
+			cfg, err := LoadFromEnvFile(path)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Logger.Level != tt.wantLevel || cfg.Logger.ServiceName != tt.wantName || cfg.Logger.Environment != EnvProduction {
+				t.Errorf("unexpected config: %+v", cfg.Logger)
+			}
+			if os.Getenv("LOG_LEVEL") != "INFO" {
+				t.Error("expected the process environment to be left untouched")
+			}
+		})
+	}
+
+	if _, err := LoadFromEnvFile(t.TempDir() + "/missing.env"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist for a missing file, got %v", err)
+	}
+}
+
+// clearEnv clears all test-related environment variables.
+func clearEnv() {
+	os.Unsetenv("LOG_LEVEL")
+	os.Unsetenv("APP_ENV")
+	os.Unsetenv("APP_NAME")
+	os.Unsetenv("LOG_LEVELS")
+	os.Unsetenv("LOG_FILE")
+	os.Unsetenv("LOG_FILE_MAX_SIZE_MB")
+	os.Unsetenv("LOG_FILE_ROTATE_INTERVAL")
+	os.Unsetenv("LOG_FILE_MAX_AGE")
+	os.Unsetenv("LOG_FILE_MAX_BACKUPS")
+	os.Unsetenv("LOG_FILE_COMPRESS")
+	os.Unsetenv("LOG_OUTPUTS")
+	os.Unsetenv("LOG_ASYNC")
+	os.Unsetenv("LOG_ASYNC_BUFFER_SIZE")
+	os.Unsetenv("LOG_ASYNC_FLUSH_INTERVAL")
+	os.Unsetenv("LOG_ASYNC_OVERFLOW")
+	os.Unsetenv("LOG_ASYNC_DROP_BELOW")
+	os.Unsetenv("LOG_SAMPLING")
+	os.Unsetenv("LOG_SAMPLING_TICK")
+	os.Unsetenv("LOG_SAMPLING_INITIAL")
+	os.Unsetenv("LOG_SAMPLING_THEREAFTER")
+	os.Unsetenv("LOG_SAMPLING_LEVELS")
+	os.Unsetenv("LOG_DEDUP")
+	os.Unsetenv("LOG_DEDUP_WINDOW")
+	os.Unsetenv("LOG_RATE_LIMIT")
+	os.Unsetenv("LOG_RATE_LIMIT_BY")
+	os.Unsetenv("LOG_RATE_LIMIT_RATE")
+	os.Unsetenv("LOG_RATE_LIMIT_BURST")
+	os.Unsetenv("LOG_RATE_LIMIT_LEVELS")
+	os.Unsetenv("LOG_RATE_LIMIT_EXEMPT_ERRORS")
+	os.Unsetenv("LOG_REDACT")
+	os.Unsetenv("LOG_REDACT_KEYS")
+	os.Unsetenv("LOG_REDACT_KEY_PATTERNS")
+	os.Unsetenv("LOG_REDACT_VALUES")
+	os.Unsetenv("LOG_REDACT_ACTION")
+	os.Unsetenv("LOG_TOKEN_KEY")
+	os.Unsetenv("LOG_TOKEN_KEY_FILE")
+	os.Unsetenv("LOG_TOKEN_KEY_ID")
+	os.Unsetenv("LOG_TRACE_ID_KEY")
+	os.Unsetenv("LOG_SPAN_ID_KEY")
+	os.Unsetenv("LOG_TRACE_FLAGS_KEY")
+	os.Unsetenv("OTEL_LOGS_EXPORTER")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_LOGS_PROTOCOL")
+	os.Unsetenv("LOG_OTLP_LEVEL")
+	os.Unsetenv("TEST_VAR")
+	os.Unsetenv("REQUIRED_VAR")
+}
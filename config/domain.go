@@ -0,0 +1,355 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Validator is implemented by configuration domains that check their values
+// beyond what their struct tags express.
+//
+// Validate may return a *ValidationError to report several values at once.
+type Validator interface {
+	Validate() error
+}
+
+// domain is a configuration domain added with Register.
+type domain struct {
+	name   string
+	target any
+}
+
+var (
+	domainsMu sync.RWMutex
+	domains   []domain
+)
+
+// Register adds a configuration domain loaded and validated by Load,
+// LoadFromEnvFile and LoadFile alongside Logger.
+//
+// target must be a pointer to a struct whose fields are bound to environment
+// variables with struct tags, as described in Bind. If the struct implements
+// Validator it is validated once bound. Problems are reported in the same
+// *ValidationError as those of the logger configuration, and target is only
+// updated if the whole configuration is valid. The loaded domain is also
+// available in Config.Domains under name.
+//
+// Register is typically called from init or before Load. It returns an error
+// if name is empty or already registered, or if target cannot be bound or
+// has an invalid default.
+//
+// Example:
+//
+//	type DatabaseConfig struct {
+//	    URL      string        `env:"DATABASE_URL,required"`
+//	    MaxConns int           `env:"DATABASE_MAX_CONNS" default:"10"`
+//	    Timeout  time.Duration `env:"DATABASE_TIMEOUT" default:"5s"`
+//	}
+//
+//	var dbConfig DatabaseConfig
+//
+//	func init() {
+//	    config.MustRegister("database", &dbConfig)
+//	}
+func Register(name string, target any) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "logger" {
+		return fmt.Errorf("config: invalid domain name '%s'", name)
+	}
+	if _, err := bindTarget(target); err != nil {
+		return err
+	}
+	// Check the struct tags and defaults of target without modifying it.
+	errs, err := bind(reflect.New(reflect.TypeOf(target).Elem()), func(string) string { return "" })
+	if err != nil {
+		return err
+	}
+	for _, fe := range errs {
+		if errors.Is(fe.Err, ErrInvalidValue) {
+			return fmt.Errorf("config: domain '%s': default of %s: %w", name, fe.Key, fe.Err)
+		}
+	}
+
+	domainsMu.Lock()
+	defer domainsMu.Unlock()
+	for _, d := range domains {
+		if d.name == name {
+			return fmt.Errorf("config: domain '%s' already registered", name)
+		}
+	}
+	domains = append(domains, domain{name: name, target: target})
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func MustRegister(name string, target any) {
+	if err := Register(name, target); err != nil {
+		panic(err)
+	}
+}
+
+// Bind sets the fields of the struct pointed to by dst from environment
+// variables, following their struct tags:
+//
+//	env:"NAME"           the variable that sets the field
+//	env:"NAME,required"  the variable must be set
+//	default:"value"      the value used when the variable is not set
+//
+// Supported field types are strings, booleans, integers, floats,
+// time.Duration, []string (comma-separated) and types implementing
+// encoding.TextUnmarshaler. Named types such as LogLevel are set from their
+// underlying type. Untagged struct fields are bound recursively; other
+// untagged fields are left untouched.
+//
+// Every missing or invalid variable is reported in a *ValidationError. Bind
+// does not load the .env file; Load does.
+//
+// Example:
+//
+//	var server struct {
+//	    Addr    string        `env:"SERVER_ADDR" default:":8080"`
+//	    Timeout time.Duration `env:"SERVER_TIMEOUT" default:"30s"`
+//	}
+//	if err := config.Bind(&server); err != nil {
+//	    log.Fatal(err)
+//	}
+func Bind(dst any) error {
+	v, err := bindTarget(dst)
+	if err != nil {
+		return err
+	}
+	errs, err := bind(v, os.Getenv)
+	if err != nil {
+		return err
+	}
+	return errs.err()
+}
+
+// bindTarget checks that dst is a non-nil pointer to a struct.
+func bindTarget(dst any) (reflect.Value, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("config: target must be a non-nil pointer to a struct, got %T", dst)
+	}
+	return v, nil
+}
+
+// bind sets the fields of the struct pointed to by v from getenv. It returns
+// the missing or invalid variables, or an error if a tag or field type is
+// not supported.
+func bind(v reflect.Value, getenv getenvFunc) (fieldErrors, error) {
+	var errs fieldErrors
+	if err := bindStruct(v.Elem(), getenv, &errs); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// bindStruct binds the fields of the struct v.
+func bindStruct(v reflect.Value, getenv getenvFunc, errs *fieldErrors) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("env")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct && !isTextUnmarshaler(field.Type) {
+				if err := bindStruct(v.Field(i), getenv, errs); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		key, opts, _ := strings.Cut(tag, ",")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return fmt.Errorf("config: field %s.%s: env tag has no variable name", t.Name(), field.Name)
+		}
+		required := false
+		for _, opt := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case "":
+			case "required":
+				required = true
+			default:
+				return fmt.Errorf("config: field %s.%s: unknown env tag option '%s'", t.Name(), field.Name, opt)
+			}
+		}
+		if !isBindable(field.Type) {
+			return fmt.Errorf("config: field %s.%s: unsupported type %s", t.Name(), field.Name, field.Type)
+		}
+
+		value := getenv(key)
+		if value == "" {
+			if required {
+				errs.check(key, ErrMissingRequiredEnvVar)
+				continue
+			}
+			if value = field.Tag.Get("default"); value == "" {
+				continue
+			}
+		}
+		if err := setField(v.Field(i), strings.TrimSpace(value)); err != nil {
+			errs.check(key, fmt.Errorf("%w: %v, got '%s'", ErrInvalidValue, err, value))
+		}
+	}
+	return nil
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	stringType          = reflect.TypeOf("")
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isTextUnmarshaler reports whether values of type t can be unmarshaled from text.
+func isTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isBindable reports whether setField supports values of type t.
+func isBindable(t reflect.Type) bool {
+	if isTextUnmarshaler(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem() == stringType
+	}
+	return false
+}
+
+// setField parses value into the field f.
+func setField(f reflect.Value, value string) error {
+	if isTextUnmarshaler(f.Type()) {
+		return f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f.Type() == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("must be a duration (e.g. 30s, 24h)")
+			}
+			f.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return errors.New("must be a non-negative integer")
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		f.SetFloat(x)
+	case reflect.Slice:
+		f.Set(reflect.ValueOf(splitList(value)).Convert(f.Type()))
+	}
+	return nil
+}
+
+// boundDomain is a registered domain loaded into a new value, not yet
+// stored into its target.
+type boundDomain struct {
+	domain
+	value reflect.Value
+}
+
+// boundDomains are the registered domains loaded by a single Load.
+type boundDomains []boundDomain
+
+// loadDomains binds and validates every registered domain into new values.
+func loadDomains(getenv getenvFunc) (boundDomains, fieldErrors) {
+	domainsMu.RLock()
+	registered := append([]domain(nil), domains...)
+	domainsMu.RUnlock()
+
+	var (
+		bound boundDomains
+		errs  fieldErrors
+	)
+	for _, d := range registered {
+		value := reflect.New(reflect.TypeOf(d.target).Elem())
+		bindErrs, err := bind(value, getenv)
+		if err != nil {
+			// Register checked the struct tags.
+			errs.check(d.name, err)
+			continue
+		}
+		errs = append(errs, bindErrs...)
+		if len(bindErrs) == 0 {
+			errs = append(errs, validateDomain(d.name, value.Interface())...)
+		}
+		bound = append(bound, boundDomain{domain: d, value: value})
+	}
+	return bound, errs
+}
+
+// commit stores the loaded values into their targets and returns them keyed
+// by domain name.
+func (b boundDomains) commit() map[string]any {
+	if len(b) == 0 {
+		return nil
+	}
+	loaded := make(map[string]any, len(b))
+	for _, d := range b {
+		reflect.ValueOf(d.target).Elem().Set(d.value.Elem())
+		loaded[d.name] = d.target
+	}
+	return loaded
+}
+
+// validateDomain validates the domain target if it implements Validator.
+func validateDomain(name string, target any) fieldErrors {
+	v, ok := target.(Validator)
+	if !ok {
+		return nil
+	}
+	err := v.Validate()
+	if err == nil {
+		return nil
+	}
+
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return append(fieldErrors(nil), verr.Errors...)
+	}
+	if !errors.Is(err, ErrInvalidValue) && !errors.Is(err, ErrMissingRequiredEnvVar) {
+		err = fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	return fieldErrors{{Key: name, Err: err}}
+}
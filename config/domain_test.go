@@ -0,0 +1,206 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDatabaseConfig is a custom configuration domain.
+type testDatabaseConfig struct {
+	URL      string        `env:"TEST_DATABASE_URL,required"`
+	MaxConns int           `env:"TEST_DATABASE_MAX_CONNS" default:"10"`
+	Timeout  time.Duration `env:"TEST_DATABASE_TIMEOUT" default:"5s"`
+	Replicas []string      `env:"TEST_DATABASE_REPLICAS"`
+	Level    LogLevel      `env:"TEST_DATABASE_LOG_LEVEL" default:"WARN"`
+	Pool     struct {
+		Idle uint `env:"TEST_DATABASE_POOL_IDLE" default:"2"`
+	}
+	internal string
+}
+
+// Validate checks the connection limits.
+func (c testDatabaseConfig) Validate() error {
+	if c.MaxConns < 1 {
+		return fmt.Errorf("max conns must be positive, got %d", c.MaxConns)
+	}
+	return nil
+}
+
+// resetDomains unregisters all configuration domains.
+func resetDomains() {
+	domainsMu.Lock()
+	domains = nil
+	domainsMu.Unlock()
+}
+
+// TestBind tests binding struct fields from environment variables.
+func TestBind(t *testing.T) {
+	t.Run("sets values and defaults", func(t *testing.T) {
+		t.Setenv("TEST_DATABASE_URL", "postgres://db")
+		t.Setenv("TEST_DATABASE_TIMEOUT", "1m")
+		t.Setenv("TEST_DATABASE_REPLICAS", "r1, r2")
+
+		var got testDatabaseConfig
+		if err := Bind(&got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := testDatabaseConfig{URL: "postgres://db", MaxConns: 10, Timeout: time.Minute, Replicas: []string{"r1", "r2"}, Level: LogLevelWarn}
+		want.Pool.Idle = 2
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		os.Unsetenv("TEST_DATABASE_URL")
+		t.Setenv("TEST_DATABASE_MAX_CONNS", "many")
+		t.Setenv("TEST_DATABASE_POOL_IDLE", "-1")
+
+		var got testDatabaseConfig
+		err := Bind(&got)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		var keys []string
+		for _, fe := range verr.Errors {
+			keys = append(keys, fe.Key)
+		}
+		if want := []string{"TEST_DATABASE_URL", "TEST_DATABASE_MAX_CONNS", "TEST_DATABASE_POOL_IDLE"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("expected %v, got %v", want, keys)
+		}
+		if !errors.Is(err, ErrMissingRequiredEnvVar) || !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("expected missing and invalid sentinels, got: %v", err)
+		}
+	})
+
+	t.Run("supports text unmarshalers", func(t *testing.T) {
+		t.Setenv("TEST_BIND_IP", "10.0.0.1")
+
+		var got struct {
+			IP net.IP `env:"TEST_BIND_IP"`
+		}
+		if err := Bind(&got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.IP.Equal(net.ParseIP("10.0.0.1")) {
+			t.Errorf("unexpected IP %v", got.IP)
+		}
+	})
+
+	t.Run("rejects invalid targets", func(t *testing.T) {
+		var unsupported struct {
+			Ports map[string]int `env:"TEST_PORTS"`
+		}
+		var badOption struct {
+			Port int `env:"TEST_PORT,optional"`
+		}
+		for _, dst := range []any{nil, testDatabaseConfig{}, &unsupported, &badOption} {
+			if err := Bind(dst); err == nil {
+				t.Errorf("expected error for %T", dst)
+			}
+		}
+	})
+}
+
+// TestRegister tests the registration of configuration domains.
+func TestRegister(t *testing.T) {
+	resetDomains()
+	defer resetDomains()
+
+	var db testDatabaseConfig
+	if err := Register("database", &db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var badDefault struct {
+		Port int `env:"TEST_PORT" default:"http"`
+	}
+	tests := []struct {
+		name   string
+		domain string
+		target any
+	}{
+		{name: "duplicate name", domain: "database", target: &testDatabaseConfig{}},
+		{name: "empty name", domain: " ", target: &testDatabaseConfig{}},
+		{name: "reserved name", domain: "logger", target: &testDatabaseConfig{}},
+		{name: "not a pointer", domain: "cache", target: testDatabaseConfig{}},
+		{name: "invalid default", domain: "server", target: &badDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Register(tt.domain, tt.target); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
+
+// TestLoad_Domains tests that registered domains are loaded and validated with the logger configuration.
+func TestLoad_Domains(t *testing.T) {
+	resetDomains()
+	defer resetDomains()
+
+	var db testDatabaseConfig
+	MustRegister("database", &db)
+
+	t.Run("reports domain problems with logger problems", func(t *testing.T) {
+		clearEnv()
+		defer clearEnv()
+		os.Setenv("APP_ENV", "production")
+		os.Setenv("APP_NAME", "svc")
+		os.Setenv("TEST_DATABASE_URL", "postgres://db")
+		os.Setenv("TEST_DATABASE_MAX_CONNS", "0")
+		defer os.Unsetenv("TEST_DATABASE_URL")
+		defer os.Unsetenv("TEST_DATABASE_MAX_CONNS")
+
+		_, err := Load()
+		var verr *ValidationError
+		if !errors.As(err, &verr) || len(verr.Errors) != 2 {
+			t.Fatalf("expected 2 problems, got %v", err)
+		}
+		if verr.Errors[0].Key != "LOG_LEVEL" || verr.Errors[1].Key != "database" {
+			t.Errorf("unexpected problems: %v", err)
+		}
+		if !errors.Is(verr.Errors[1], ErrInvalidValue) || !strings.Contains(err.Error(), "max conns must be positive") {
+			t.Errorf("expected domain error to wrap ErrInvalidValue, got %v", err)
+		}
+		if db.URL != "" {
+			t.Error("expected the domain to be left untouched on error")
+		}
+	})
+
+	t.Run("loads domain", func(t *testing.T) {
+		clearEnv()
+		defer clearEnv()
+		os.Setenv("LOG_LEVEL", "INFO")
+		os.Setenv("APP_ENV", "production")
+		os.Setenv("APP_NAME", "svc")
+		os.Setenv("TEST_DATABASE_URL", "postgres://db")
+		defer os.Unsetenv("TEST_DATABASE_URL")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if db.URL != "postgres://db" || db.MaxConns != 10 {
+			t.Errorf("unexpected domain %+v", db)
+		}
+		if cfg.Domains["database"] != &db {
+			t.Errorf("expected Config.Domains to hold the registered target")
+		}
+
+		db.MaxConns = 0
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("expected Config.Validate to validate domains, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// GetEnv retrieves an environment variable with a fallback default value.
+//
+// This is a convenience function for optional environment variables.
+func GetEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// RequireEnv retrieves a required environment variable or returns an error.
+//
+// This is useful for loading additional required configuration values.
+func RequireEnv(key string) (string, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingRequiredEnvVar, key)
+	}
+	return value, nil
+}
+
+// GetInt retrieves an integer environment variable, or defaultValue if it is
+// not set.
+//
+// It returns defaultValue and an error wrapping ErrInvalidValue if the
+// variable is not an integer.
+//
+// Example:
+//
+//	workers, err := config.GetInt("WORKERS", 4)
+func GetInt(key string, defaultValue int) (int, error) {
+	r := &envReader{getenv: os.Getenv}
+	value := defaultValue
+	r.setInt(key, &value)
+	return value, r.errs.err()
+}
+
+// GetDuration retrieves a duration environment variable such as "30s", or
+// defaultValue if it is not set.
+//
+// It returns defaultValue and an error wrapping ErrInvalidValue if the
+// variable is not a duration.
+//
+// Example:
+//
+//	timeout, err := config.GetDuration("HTTP_TIMEOUT", 10*time.Second)
+func GetDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	r := &envReader{getenv: os.Getenv}
+	value := defaultValue
+	r.setDuration(key, &value)
+	return value, r.errs.err()
+}
+
+// GetBool retrieves a boolean environment variable ("true", "false", "1",
+// "0", ...), or defaultValue if it is not set.
+//
+// It returns defaultValue and an error wrapping ErrInvalidValue if the
+// variable is not a boolean.
+//
+// Example:
+//
+//	debug, err := config.GetBool("FEATURE_DEBUG", false)
+func GetBool(key string, defaultValue bool) (bool, error) {
+	r := &envReader{getenv: os.Getenv}
+	value := defaultValue
+	r.setBool(key, &value)
+	return value, r.errs.err()
+}
+
+// GetList retrieves a comma-separated environment variable as a list, or
+// defaultValue if it is not set or has no items. Items are trimmed and empty
+// items are dropped.
+//
+// If validate is not nil, every item is checked with it. It returns
+// defaultValue and an error wrapping ErrInvalidValue if an item is rejected.
+//
+// Example:
+//
+//	origins, err := config.GetList("CORS_ORIGINS", []string{"*"}, func(origin string) error {
+//	    _, err := url.Parse(origin)
+//	    return err
+//	})
+func GetList(key string, defaultValue []string, validate func(item string) error) ([]string, error) {
+	items := splitList(os.Getenv(key))
+	if len(items) == 0 {
+		return defaultValue, nil
+	}
+	if validate != nil {
+		for _, item := range items {
+			if err := validate(item); err != nil {
+				errs := fieldErrors{{Key: key, Err: fmt.Errorf("%w: item '%s': %v", ErrInvalidValue, item, err)}}
+				return defaultValue, errs.err()
+			}
+		}
+	}
+	return items, nil
+}
+
+// splitList splits a comma-separated value into its trimmed, non-empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
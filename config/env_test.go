@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGetInt tests the GetInt function.
+func TestGetInt(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      int
+		wantError bool
+	}{
+		{name: "returns default when not set", value: "", want: 4},
+		{name: "parses value", value: " 16 ", want: 16},
+		{name: "rejects non-integer", value: "many", want: 4, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_WORKERS", tt.value)
+
+			got, err := GetInt("TEST_WORKERS", 4)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("expected ErrInvalidValue but got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGetDuration tests the GetDuration function.
+func TestGetDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      time.Duration
+		wantError bool
+	}{
+		{name: "returns default when not set", value: "", want: time.Second},
+		{name: "parses value", value: "1m30s", want: 90 * time.Second},
+		{name: "rejects non-duration", value: "90", want: time.Second, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_TIMEOUT", tt.value)
+
+			got, err := GetDuration("TEST_TIMEOUT", time.Second)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("expected ErrInvalidValue but got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGetBool tests the GetBool function.
+func TestGetBool(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      bool
+		wantError bool
+	}{
+		{name: "returns default when not set", value: "", want: true},
+		{name: "parses value", value: "0", want: false},
+		{name: "rejects non-boolean", value: "yes", want: true, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_FEATURE", tt.value)
+
+			got, err := GetBool("TEST_FEATURE", true)
+			if tt.wantError != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("expected ErrInvalidValue but got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGetList tests the GetList function.
+func TestGetList(t *testing.T) {
+	noSpaces := func(item string) error {
+		if strings.Contains(item, " ") {
+			return errors.New("must not contain spaces")
+		}
+		return nil
+	}
+	tests := []struct {
+		name     string
+		value    string
+		setEnv   bool
+		validate func(string) error
+		want     []string
+		wantErr  bool
+	}{
+		{name: "returns default when not set", want: []string{"*"}},
+		{name: "splits and trims items", value: " a.com, b.com ,,", setEnv: true, want: []string{"a.com", "b.com"}},
+		{name: "returns default when empty", value: " , ", setEnv: true, want: []string{"*"}},
+		{name: "accepts valid items", value: "a.com,b.com", setEnv: true, validate: noSpaces, want: []string{"a.com", "b.com"}},
+		{name: "returns default and error for invalid item", value: "a.com,b c", setEnv: true, validate: noSpaces, want: []string{"*"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Unsetenv("TEST_ORIGINS")
+			if tt.setEnv {
+				t.Setenv("TEST_ORIGINS", tt.value)
+			}
+
+			got, err := GetList("TEST_ORIGINS", []string{"*"}, tt.validate)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidValue) {
+					t.Errorf("expected ErrInvalidValue, got %v", err)
+				}
+				if err != nil && !strings.Contains(err.Error(), "TEST_ORIGINS") {
+					t.Errorf("expected error to name the variable, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Preset holds the defaults the logger applies in an environment.
+//
+// The zero value is production-like: JSON output, no colors, no sampling,
+// stack traces from ERROR, and no .env loading.
+type Preset struct {
+	// Encoding is the default encoding of stdout and stderr outputs. Other
+	// outputs default to JSON. Empty means JSON.
+	Encoding Encoding
+
+	// Color colors the level of console-encoded stdout and stderr outputs.
+	Color bool
+
+	// Sampling enables LoggerConfig.Sampling unless it is explicitly
+	// disabled, with the default policy unless one is configured: per
+	// second, the first 100 entries with the same level and message are
+	// logged, then every 100th.
+	Sampling bool
+
+	// StacktraceLevel is the minimum level at which a stack trace is
+	// attached to entries. Empty means ERROR.
+	StacktraceLevel LogLevel
+
+	// LoadEnvFiles loads the .env files before reading environment
+	// variables (see Load). Leave it false for deployed environments, where
+	// variables must come from the infrastructure.
+	LoadEnvFiles bool
+}
+
+// Validate checks if the preset is valid.
+func (p Preset) Validate() error {
+	if p.Encoding != "" {
+		if err := p.Encoding.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.StacktraceLevel != "" {
+		if err := p.StacktraceLevel.Validate(); err != nil {
+			return fmt.Errorf("stacktrace level: %w", err)
+		}
+	}
+	return nil
+}
+
+var (
+	presetsMu sync.RWMutex
+	presets   = map[Environment]Preset{
+		EnvDevelopment: {Encoding: EncodingConsole, Color: true, LoadEnvFiles: true},
+		EnvTest:        {Encoding: EncodingConsole, LoadEnvFiles: true},
+		EnvStaging:     {Encoding: EncodingJSON},
+		EnvProduction:  {Encoding: EncodingJSON},
+	}
+)
+
+// RegisterEnvironment accepts env as an environment with the given preset,
+// or replaces the preset of an existing environment, including the built-in
+// ones.
+//
+// It is typically called from init or before Load, since the preset decides
+// whether the .env files are loaded.
+//
+// Example:
+//
+//	// local-ci logs like production but loads the .env files.
+//	preset, _ := config.EnvProduction.Preset()
+//	preset.LoadEnvFiles = true
+//	if err := config.RegisterEnvironment("local-ci", preset); err != nil {
+//	    log.Fatal(err)
+//	}
+func RegisterEnvironment(env Environment, preset Preset) error {
+	if strings.TrimSpace(string(env)) == "" || strings.ToLower(string(env)) != string(env) {
+		return fmt.Errorf("%w: environment name must be non-empty and lowercase, got '%s'", ErrInvalidValue, env)
+	}
+	if err := preset.Validate(); err != nil {
+		return fmt.Errorf("environment '%s': %w", env, err)
+	}
+
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	presets[env] = preset
+	return nil
+}
+
+// Preset returns the preset of the environment and whether the environment
+// is known.
+func (e Environment) Preset() (Preset, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	preset, ok := presets[e]
+	return preset, ok
+}
+
+// environments returns the names of the known environments, sorted.
+func environments() []string {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	names := make([]string, 0, len(presets))
+	for env := range presets {
+		names = append(names, "'"+string(env)+"'")
+	}
+	sort.Strings(names)
+	return names
+}
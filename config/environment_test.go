@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// restorePresets restores the built-in environment presets after a test.
+func restorePresets(t *testing.T) {
+	t.Helper()
+	presetsMu.RLock()
+	saved := make(map[Environment]Preset, len(presets))
+	for env, preset := range presets {
+		saved[env] = preset
+	}
+	presetsMu.RUnlock()
+
+	t.Cleanup(func() {
+		presetsMu.Lock()
+		presets = saved
+		presetsMu.Unlock()
+	})
+}
+
+// TestRegisterEnvironment tests adding and overriding environments.
+func TestRegisterEnvironment(t *testing.T) {
+	restorePresets(t)
+
+	if err := Environment("local-ci").Validate(); err == nil {
+		t.Fatal("expected unregistered environment to be invalid")
+	}
+	preset := Preset{Encoding: EncodingConsole, Sampling: true, StacktraceLevel: LogLevelWarn}
+	if err := RegisterEnvironment("local-ci", preset); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Environment("local-ci").Validate(); err != nil {
+		t.Errorf("expected registered environment to be valid, got %v", err)
+	}
+	if got, ok := Environment("local-ci").Preset(); !ok || got != preset {
+		t.Errorf("expected registered preset, got %+v", got)
+	}
+
+	if err := RegisterEnvironment(EnvProduction, Preset{Sampling: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := EnvProduction.Preset(); !got.Sampling {
+		t.Error("expected built-in preset to be overridden")
+	}
+
+	tests := []struct {
+		name   string
+		env    Environment
+		preset Preset
+	}{
+		{name: "empty name", env: " ", preset: Preset{}},
+		{name: "uppercase name", env: "QA", preset: Preset{}},
+		{name: "invalid encoding", env: "qa", preset: Preset{Encoding: "xml"}},
+		{name: "invalid stacktrace level", env: "qa", preset: Preset{StacktraceLevel: "LOUD"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterEnvironment(tt.env, tt.preset); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
+
+// TestLoadEnvFile_Preset tests that the preset decides whether .env files are loaded.
+func TestLoadEnvFile_Preset(t *testing.T) {
+	restorePresets(t)
+	if err := RegisterEnvironment("local-ci", Preset{LoadEnvFiles: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, []byte("TEST_PRESET_VALUE=loaded\n"), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+
+	tests := []struct {
+		appEnv   string
+		wantLoad bool
+	}{
+		{appEnv: "staging", wantLoad: false},
+		{appEnv: "test", wantLoad: true},
+		{appEnv: "local-ci", wantLoad: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.appEnv, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("TEST_PRESET_VALUE", "")
+			os.Unsetenv("TEST_PRESET_VALUE")
+
+			if err := (LoadOptions{EnvFiles: []string{envFile}}).loadEnvFile(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if loaded := os.Getenv("TEST_PRESET_VALUE") == "loaded"; loaded != tt.wantLoad {
+				t.Errorf("loaded = %v, want %v", loaded, tt.wantLoad)
+			}
+		})
+	}
+}
@@ -0,0 +1,655 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the layout of configuration files. Keys use snake_case and
+// mirror the environment variables, e.g.:
+//
+//	logger:
+//	  level: INFO
+//	  environment: production
+//	  service_name: my-api
+//	  levels:
+//	    db: DEBUG
+//	  outputs:
+//	    - type: stdout
+//	      encoding: console
+//	    - type: file
+//	      path: /var/log/my-api.log
+//	      max_size_mb: 100
+//	  async:
+//	    enabled: true
+//	    overflow: drop_below
+//	    drop_below: WARN
+//	  sampling:
+//	    enabled: true
+//	    levels:
+//	      DEBUG:
+//	        thereafter: 1000
+type fileConfig struct {
+	Logger fileLoggerConfig `yaml:"logger" json:"logger" toml:"logger"`
+}
+
+// fileLoggerConfig is the logger section of a configuration file.
+type fileLoggerConfig struct {
+	Level        string                 `yaml:"level" json:"level" toml:"level"`
+	Environment  string                 `yaml:"environment" json:"environment" toml:"environment"`
+	ServiceName  string                 `yaml:"service_name" json:"service_name" toml:"service_name"`
+	Levels       map[string]string      `yaml:"levels" json:"levels" toml:"levels"`
+	File         fileFileConfig         `yaml:"file" json:"file" toml:"file"`
+	Outputs      []fileOutputConfig     `yaml:"outputs" json:"outputs" toml:"outputs"`
+	Async        fileAsyncConfig        `yaml:"async" json:"async" toml:"async"`
+	Sampling     fileSamplingConfig     `yaml:"sampling" json:"sampling" toml:"sampling"`
+	Dedup        fileDedupConfig        `yaml:"dedup" json:"dedup" toml:"dedup"`
+	RateLimit    fileRateLimitConfig    `yaml:"rate_limit" json:"rate_limit" toml:"rate_limit"`
+	Redaction    fileRedactionConfig    `yaml:"redaction" json:"redaction" toml:"redaction"`
+	Tokenization fileTokenizationConfig `yaml:"tokenization" json:"tokenization" toml:"tokenization"`
+	Trace        fileTraceConfig        `yaml:"trace" json:"trace" toml:"trace"`
+	OTLP         fileOTLPConfig         `yaml:"otlp" json:"otlp" toml:"otlp"`
+}
+
+// fileFileConfig holds the settings of a file output. Durations are strings
+// such as "24h".
+type fileFileConfig struct {
+	Path           string `yaml:"path" json:"path" toml:"path"`
+	MaxSizeMB      int    `yaml:"max_size_mb" json:"max_size_mb" toml:"max_size_mb"`
+	RotateInterval string `yaml:"rotate_interval" json:"rotate_interval" toml:"rotate_interval"`
+	MaxAge         string `yaml:"max_age" json:"max_age" toml:"max_age"`
+	MaxBackups     int    `yaml:"max_backups" json:"max_backups" toml:"max_backups"`
+	Compress       bool   `yaml:"compress" json:"compress" toml:"compress"`
+}
+
+// fileOutputConfig is an entry of the outputs list. File settings are
+// inlined, as in LOG_OUTPUTS.
+type fileOutputConfig struct {
+	Type           string `yaml:"type" json:"type" toml:"type"`
+	Level          string `yaml:"level" json:"level" toml:"level"`
+	Encoding       string `yaml:"encoding" json:"encoding" toml:"encoding"`
+	Address        string `yaml:"address" json:"address" toml:"address"`
+	fileFileConfig `yaml:",inline"`
+}
+
+// fileAsyncConfig is the async section of a configuration file.
+type fileAsyncConfig struct {
+	Enabled       bool   `yaml:"enabled" json:"enabled" toml:"enabled"`
+	BufferSize    int    `yaml:"buffer_size" json:"buffer_size" toml:"buffer_size"`
+	FlushInterval string `yaml:"flush_interval" json:"flush_interval" toml:"flush_interval"`
+	Overflow      string `yaml:"overflow" json:"overflow" toml:"overflow"`
+	DropBelow     string `yaml:"drop_below" json:"drop_below" toml:"drop_below"`
+}
+
+// fileSamplingPolicy holds sampling settings. The tick is a string such as "1s".
+type fileSamplingPolicy struct {
+	Tick       string `yaml:"tick" json:"tick" toml:"tick"`
+	Initial    int    `yaml:"initial" json:"initial" toml:"initial"`
+	Thereafter int    `yaml:"thereafter" json:"thereafter" toml:"thereafter"`
+}
+
+// fileSamplingConfig is the sampling section of a configuration file.
+// Per-level overrides are keyed by level. Enabled is nil when not set, so
+// that the environment's preset applies.
+type fileSamplingConfig struct {
+	Enabled            *bool                         `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Levels             map[string]fileSamplingPolicy `yaml:"levels" json:"levels" toml:"levels"`
+	fileSamplingPolicy `yaml:",inline"`
+}
+
+// fileDedupConfig is the dedup section of a configuration file.
+type fileDedupConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Window  string `yaml:"window" json:"window" toml:"window"`
+}
+
+// fileRateLimit holds the settings of a rate limit.
+type fileRateLimit struct {
+	Rate  float64 `yaml:"rate" json:"rate" toml:"rate"`
+	Burst int     `yaml:"burst" json:"burst" toml:"burst"`
+}
+
+// fileRateLimitConfig is the rate_limit section of a configuration file.
+// Per-level limits are keyed by level.
+type fileRateLimitConfig struct {
+	Enabled       bool                     `yaml:"enabled" json:"enabled" toml:"enabled"`
+	By            string                   `yaml:"by" json:"by" toml:"by"`
+	ExemptErrors  bool                     `yaml:"exempt_errors" json:"exempt_errors" toml:"exempt_errors"`
+	Levels        map[string]fileRateLimit `yaml:"levels" json:"levels" toml:"levels"`
+	fileRateLimit `yaml:",inline"`
+}
+
+// fileRedactionRule is an entry of the redaction rules.
+type fileRedactionRule struct {
+	Keys          []string `yaml:"keys" json:"keys" toml:"keys"`
+	KeyPatterns   []string `yaml:"key_patterns" json:"key_patterns" toml:"key_patterns"`
+	ValuePatterns []string `yaml:"value_patterns" json:"value_patterns" toml:"value_patterns"`
+	Action        string   `yaml:"action" json:"action" toml:"action"`
+}
+
+// fileRedactionConfig is the redaction section of a configuration file.
+type fileRedactionConfig struct {
+	Enabled bool                `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Rules   []fileRedactionRule `yaml:"rules" json:"rules" toml:"rules"`
+}
+
+// fileTokenizationConfig is the tokenization section of a configuration
+// file. The key itself is only read from a key file or from environment, so
+// that configuration files hold no secret.
+type fileTokenizationConfig struct {
+	KeyID   string `yaml:"key_id" json:"key_id" toml:"key_id"`
+	KeyFile string `yaml:"key_file" json:"key_file" toml:"key_file"`
+}
+
+// fileTraceConfig is the trace section of a configuration file.
+type fileTraceConfig struct {
+	TraceIDKey    string `yaml:"trace_id_key" json:"trace_id_key" toml:"trace_id_key"`
+	SpanIDKey     string `yaml:"span_id_key" json:"span_id_key" toml:"span_id_key"`
+	TraceFlagsKey string `yaml:"trace_flags_key" json:"trace_flags_key" toml:"trace_flags_key"`
+}
+
+// fileOTLPConfig is the otlp section of a configuration file.
+type fileOTLPConfig struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Protocol string `yaml:"protocol" json:"protocol" toml:"protocol"`
+	Endpoint string `yaml:"endpoint" json:"endpoint" toml:"endpoint"`
+	Level    string `yaml:"level" json:"level" toml:"level"`
+}
+
+// LoadFile reads configuration from a YAML (.yaml, .yml), JSON (.json) or
+// TOML (.toml) file and validates it.
+//
+// Environment variables override the values from the file, using the same
+// names as Load (LOG_LEVEL, APP_ENV, LOG_OUTPUTS, ...); as with Load, the
+// .env file is loaded first outside production. Registered domains are
+// loaded from environment variables only. Variables that are required
+// by Load may therefore be set in the file instead.
+//
+// Invalid values are reported all at once in a *ValidationError, keyed by
+// the file, line and key of each offending value, e.g.
+//
+//	config.yaml:9: logger.outputs[1].max_age: output 1 (file): invalid configuration value: ...
+//
+// Example:
+//
+//	cfg, err := config.LoadFile("config/logging.yaml")
+//	if err != nil {
+//	    log.Fatalf("failed to load configuration: %v", err)
+//	}
+func LoadFile(path string) (Config, error) {
+	return LoadOptions{}.LoadFile(path)
+}
+
+// LoadFile is like the package-level LoadFile, reading the environment
+// variables and .env files selected by o.
+func (o LoadOptions) LoadFile(path string) (Config, error) {
+	if err := o.loadEnvFile(); err != nil {
+		return Config{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	var (
+		raw  fileConfig
+		locs = fileLocations{path: path, lines: make(map[string]int)}
+	)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = decodeYAML(data, &raw, locs.lines)
+	case ".json":
+		err = decodeJSON(data, &raw, locs.lines)
+	case ".toml":
+		err = decodeTOML(data, &raw, locs.lines)
+	default:
+		return Config{}, fmt.Errorf("%w: %s: unsupported config file format '%s' (use .yaml, .yml, .json or .toml)", ErrInvalidValue, path, ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %s: %v", ErrInvalidValue, path, err)
+	}
+
+	loggerCfg, errs := raw.Logger.toLoggerConfig(locs)
+	errs = append(errs, o.rename(applyEnv(&loggerCfg, o.lookup(os.Getenv)))...)
+	for _, fe := range loggerCfg.validateFields() {
+		key := "logger." + fe.Key
+		if sub := subkey(fe.Err); sub != "" {
+			key += "." + sub
+		}
+		errs.check(locs.locate(key), fe.Err)
+	}
+	domains, domainErrs := loadDomains(os.Getenv)
+	errs = append(errs, domainErrs...)
+	if err := errs.err(); err != nil {
+		return Config{}, err
+	}
+
+	return Config{Logger: loggerCfg, Domains: domains.commit()}, nil
+}
+
+// toLoggerConfig converts the logger section, normalizing values as the
+// environment loader does. It returns the values that could not be parsed.
+func (f fileLoggerConfig) toLoggerConfig(locs fileLocations) (LoggerConfig, fieldErrors) {
+	cfg := LoggerConfig{
+		Level:       LogLevel(strings.ToUpper(strings.TrimSpace(f.Level))),
+		Environment: Environment(strings.ToLower(strings.TrimSpace(f.Environment))),
+		ServiceName: f.ServiceName,
+		Async: AsyncConfig{
+			Enabled:    f.Async.Enabled,
+			BufferSize: f.Async.BufferSize,
+			Overflow:   OverflowPolicy(strings.ToLower(strings.TrimSpace(f.Async.Overflow))),
+			DropBelow:  LogLevel(strings.ToUpper(strings.TrimSpace(f.Async.DropBelow))),
+		},
+		Trace: TraceConfig{
+			TraceIDKey:    strings.TrimSpace(f.Trace.TraceIDKey),
+			SpanIDKey:     strings.TrimSpace(f.Trace.SpanIDKey),
+			TraceFlagsKey: strings.TrimSpace(f.Trace.TraceFlagsKey),
+		},
+		OTLP: OTLPConfig{
+			Enabled:  f.OTLP.Enabled,
+			Protocol: OTLPProtocol(strings.ToLower(strings.TrimSpace(f.OTLP.Protocol))),
+			Endpoint: strings.TrimSpace(f.OTLP.Endpoint),
+			Level:    LogLevel(strings.ToUpper(strings.TrimSpace(f.OTLP.Level))),
+		},
+	}
+
+	if len(f.Levels) > 0 {
+		cfg.Levels = make(map[string]LogLevel, len(f.Levels))
+		for name, level := range f.Levels {
+			cfg.Levels[name] = LogLevel(strings.ToUpper(strings.TrimSpace(level)))
+		}
+	}
+
+	var errs fieldErrors
+	cfg.File = f.File.toFileConfig(locs, "logger.file", &errs)
+	cfg.Async.FlushInterval = locs.duration("logger.async.flush_interval", f.Async.FlushInterval, &errs)
+	cfg.Sampling = f.Sampling.toSamplingConfig(locs, "logger.sampling", &errs)
+	cfg.Dedup = DedupConfig{
+		Enabled: f.Dedup.Enabled,
+		Window:  locs.duration("logger.dedup.window", f.Dedup.Window, &errs),
+	}
+	cfg.RateLimit = f.RateLimit.toRateLimitConfig()
+	cfg.Redaction = RedactionConfig{Enabled: f.Redaction.Enabled}
+	for _, rule := range f.Redaction.Rules {
+		cfg.Redaction.Rules = append(cfg.Redaction.Rules, RedactionRule{
+			Keys:          rule.Keys,
+			KeyPatterns:   rule.KeyPatterns,
+			ValuePatterns: rule.ValuePatterns,
+			Action:        RedactAction(strings.ToLower(strings.TrimSpace(rule.Action))),
+		})
+	}
+	cfg.Tokenization = TokenizationConfig{KeyID: f.Tokenization.KeyID, KeyFile: f.Tokenization.KeyFile}
+
+	for i, out := range f.Outputs {
+		file := out.toFileConfig(locs, fmt.Sprintf("logger.outputs[%d]", i), &errs)
+		cfg.Outputs = append(cfg.Outputs, OutputConfig{
+			Type:     OutputType(strings.ToLower(strings.TrimSpace(out.Type))),
+			Level:    LogLevel(strings.ToUpper(strings.TrimSpace(out.Level))),
+			Encoding: Encoding(strings.ToLower(strings.TrimSpace(out.Encoding))),
+			Address:  strings.TrimSpace(out.Address),
+			File:     file,
+		})
+	}
+
+	return cfg, errs
+}
+
+// toFileConfig converts file output settings found under key, recording the
+// values that could not be parsed in errs.
+func (f fileFileConfig) toFileConfig(locs fileLocations, key string, errs *fieldErrors) FileConfig {
+	return FileConfig{
+		Path:           f.Path,
+		MaxSizeMB:      f.MaxSizeMB,
+		RotateInterval: locs.duration(key+".rotate_interval", f.RotateInterval, errs),
+		MaxAge:         locs.duration(key+".max_age", f.MaxAge, errs),
+		MaxBackups:     f.MaxBackups,
+		Compress:       f.Compress,
+	}
+}
+
+// toSamplingConfig converts the sampling section found under key, recording
+// the values that could not be parsed in errs.
+func (f fileSamplingConfig) toSamplingConfig(locs fileLocations, key string, errs *fieldErrors) SamplingConfig {
+	policy := f.toSamplingPolicy(locs, key, errs)
+	cfg := SamplingConfig{
+		Tick:       policy.Tick,
+		Initial:    policy.Initial,
+		Thereafter: policy.Thereafter,
+	}
+	if f.Enabled != nil {
+		cfg.Enabled, cfg.Disabled = *f.Enabled, !*f.Enabled
+	}
+	if len(f.Levels) > 0 {
+		cfg.Levels = make(map[LogLevel]SamplingPolicy, len(f.Levels))
+		for level, override := range f.Levels {
+			cfg.Levels[LogLevel(strings.ToUpper(strings.TrimSpace(level)))] = override.toSamplingPolicy(locs, key+".levels."+level, errs)
+		}
+	}
+	return cfg
+}
+
+// toSamplingPolicy converts sampling settings found under key, recording the
+// values that could not be parsed in errs.
+func (f fileSamplingPolicy) toSamplingPolicy(locs fileLocations, key string, errs *fieldErrors) SamplingPolicy {
+	return SamplingPolicy{
+		Tick:       locs.duration(key+".tick", f.Tick, errs),
+		Initial:    f.Initial,
+		Thereafter: f.Thereafter,
+	}
+}
+
+// toRateLimitConfig converts the rate_limit section.
+func (f fileRateLimitConfig) toRateLimitConfig() RateLimitConfig {
+	cfg := RateLimitConfig{
+		Enabled:      f.Enabled,
+		By:           RateLimitKey(strings.ToLower(strings.TrimSpace(f.By))),
+		Rate:         f.Rate,
+		Burst:        f.Burst,
+		ExemptErrors: f.ExemptErrors,
+	}
+	if len(f.Levels) > 0 {
+		cfg.Levels = make(map[LogLevel]RateLimit, len(f.Levels))
+		for level, limit := range f.Levels {
+			cfg.Levels[LogLevel(strings.ToUpper(strings.TrimSpace(level)))] = RateLimit(limit)
+		}
+	}
+	return cfg
+}
+
+// fileLocations maps the keys of a configuration file, such as
+// "logger.outputs[1].level", to the line where they are defined.
+type fileLocations struct {
+	path  string
+	lines map[string]int
+}
+
+// locate prefixes key with the file and the line of key, or of its closest
+// enclosing key if key itself is not in the file (e.g. it has a default).
+// Keys match regardless of case, as level names do.
+func (l fileLocations) locate(key string) string {
+	for k := key; k != ""; k = parentKey(k) {
+		if line, ok := l.line(k); ok {
+			return fmt.Sprintf("%s:%d: %s", l.path, line, key)
+		}
+	}
+	return fmt.Sprintf("%s: %s", l.path, key)
+}
+
+// line returns the line of key, matched regardless of case if key itself
+// is not in the file.
+func (l fileLocations) line(key string) (int, bool) {
+	if line, ok := l.lines[key]; ok {
+		return line, true
+	}
+	for k, line := range l.lines {
+		if strings.EqualFold(k, key) {
+			return line, true
+		}
+	}
+	return 0, false
+}
+
+// duration parses the optional duration value found under key, recording
+// it in errs if it is invalid.
+func (l fileLocations) duration(key, value string, errs *fieldErrors) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		errs.check(l.locate(key), fmt.Errorf("%w: must be a duration (e.g. 30s, 24h), got '%s'", ErrInvalidValue, value))
+		return 0
+	}
+	return d
+}
+
+// parentKey returns the key enclosing key: "a.b[1]" for "a.b[1].c", "a.b"
+// for "a.b[1]" and "" for "a".
+func parentKey(key string) string {
+	if strings.HasSuffix(key, "]") {
+		if i := strings.LastIndexByte(key, '['); i >= 0 {
+			return key[:i]
+		}
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+// joinKey appends name to the key path.
+func joinKey(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// decodeYAML decodes a YAML document into dst, rejecting unknown keys, and
+// records the line of every key in lines.
+func decodeYAML(data []byte, dst *fileConfig, lines map[string]int) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	yamlLocations(&root, "", lines)
+	return nil
+}
+
+// yamlLocations records the line of every key below node.
+func yamlLocations(node *yaml.Node, path string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			yamlLocations(child, path, lines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := joinKey(path, node.Content[i].Value)
+			lines[key] = node.Content[i].Line
+			yamlLocations(node.Content[i+1], key, lines)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			key := fmt.Sprintf("%s[%d]", path, i)
+			lines[key] = child.Line
+			yamlLocations(child, key, lines)
+		}
+	}
+}
+
+// decodeJSON decodes a JSON document into dst, rejecting unknown keys, and
+// records the line of every key in lines.
+func decodeJSON(data []byte, dst *fileConfig, lines map[string]int) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("line %d: %v", lineAt(data, syntaxErr.Offset), err)
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("line %d: %v", lineAt(data, typeErr.Offset), err)
+		}
+		return err
+	}
+
+	return jsonLocations(json.NewDecoder(bytes.NewReader(data)), data, "", false, lines)
+}
+
+// jsonLocations walks the next JSON value and records the line of every key
+// below it. element reports whether the value is an array element, whose
+// own line is recorded under path.
+func jsonLocations(dec *json.Decoder, data []byte, path string, element bool, lines map[string]int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if element {
+		lines[path] = lineAt(data, dec.InputOffset())
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := joinKey(path, fmt.Sprint(tok))
+			lines[key] = lineAt(data, dec.InputOffset())
+			if err := jsonLocations(dec, data, key, false, lines); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := jsonLocations(dec, data, fmt.Sprintf("%s[%d]", path, i), true, lines); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	}
+	return err
+}
+
+// lineAt returns the 1-based line of the byte at offset.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// decodeTOML decodes a TOML document into dst, rejecting unknown keys, and
+// records the line of every key in lines.
+func decodeTOML(data []byte, dst *fileConfig, lines map[string]int) error {
+	tomlLocations(data, lines)
+
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(dst)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		key := undecoded[0].String()
+		if line, ok := lines[key]; ok {
+			return fmt.Errorf("line %d: unknown key '%s'", line, key)
+		}
+		return fmt.Errorf("unknown key '%s'", key)
+	}
+	return nil
+}
+
+// tomlLocations records the line of the keys and tables of a TOML document.
+// Arrays of tables are indexed as in "logger.outputs[1]". Inline tables are
+// recorded as a single key. Comments and the lines continuing multi-line
+// strings and arrays are skipped.
+func tomlLocations(data []byte, lines map[string]int) {
+	var (
+		table   string
+		arrays  = make(map[string]int)
+		scanner tomlScanner
+	)
+	for n, line := range strings.Split(string(data), "\n") {
+		code, continued := scanner.scan(line)
+		if continued {
+			continue
+		}
+		line = strings.TrimSpace(code)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "[["):
+			name := tomlKey(strings.TrimSuffix(strings.TrimPrefix(line, "[["), "]]"))
+			table = fmt.Sprintf("%s[%d]", name, arrays[name])
+			arrays[name]++
+			lines[table] = n + 1
+		case strings.HasPrefix(line, "["):
+			table = tomlKey(strings.TrimSuffix(strings.TrimPrefix(line, "["), "]"))
+			lines[table] = n + 1
+		default:
+			if key, _, ok := strings.Cut(line, "="); ok {
+				lines[joinKey(table, tomlKey(key))] = n + 1
+			}
+		}
+	}
+}
+
+// tomlScanner follows the strings, arrays and inline tables of a TOML
+// document across lines, so that only lines starting a key or table are
+// read as such.
+type tomlScanner struct {
+	// quote is the delimiter of the string being read, if any.
+	quote string
+	// depth is the nesting of the arrays and inline tables being read.
+	depth int
+}
+
+// scan reads the next line of the document. It returns the line without
+// its comment, and whether it continues a multi-line string or array.
+func (s *tomlScanner) scan(line string) (code string, continued bool) {
+	continued = s.quote != "" || s.depth > 0
+	header := !continued && strings.HasPrefix(strings.TrimSpace(line), "[")
+	value := continued
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if s.quote != "" {
+			switch {
+			case c == '\\' && s.quote[0] == '"':
+				i++
+			case strings.HasPrefix(line[i:], s.quote):
+				i += len(s.quote) - 1
+				s.quote = ""
+			}
+			continue
+		}
+		switch {
+		case c == '#':
+			return line[:i], continued
+		case strings.HasPrefix(line[i:], `"""`) || strings.HasPrefix(line[i:], "'''"):
+			s.quote = line[i : i+3]
+			i += 2
+		case c == '"' || c == '\'':
+			s.quote = string(c)
+		case c == '=' && !header:
+			value = true
+		case value && (c == '[' || c == '{'):
+			s.depth++
+		case value && (c == ']' || c == '}'):
+			s.depth--
+		}
+	}
+	if len(s.quote) == 1 {
+		// Single-line strings do not continue on the next line.
+		s.quote = ""
+	}
+	return line, continued
+}
+
+// tomlKey normalizes a possibly dotted and quoted TOML key.
+func tomlKey(key string) string {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
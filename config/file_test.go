@@ -0,0 +1,378 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes content to a file with the given name in a
+// temporary directory and returns its path.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+// TestLoadFile tests that YAML, JSON and TOML files load the same configuration.
+func TestLoadFile(t *testing.T) {
+	want := LoggerConfig{
+		Level:       LogLevelInfo,
+		Environment: EnvProduction,
+		ServiceName: "file-service",
+		Levels:      map[string]LogLevel{"db": LogLevelDebug},
+		Outputs: []OutputConfig{
+			{Type: OutputStdout, Encoding: EncodingConsole},
+			{Type: OutputFile, Level: LogLevelWarn, File: FileConfig{Path: "/var/log/app.log", MaxSizeMB: 100, RotateInterval: 24 * time.Hour}},
+		},
+		Async: AsyncConfig{Enabled: true, FlushInterval: 2 * time.Second, Overflow: OverflowDropBelow, DropBelow: LogLevelWarn},
+		Sampling: SamplingConfig{
+			Enabled: true,
+			Tick:    2 * time.Second,
+			Levels:  map[LogLevel]SamplingPolicy{LogLevelDebug: {Initial: 10, Thereafter: 1000}},
+		},
+		Dedup:     DedupConfig{Enabled: true, Window: 30 * time.Second},
+		RateLimit: RateLimitConfig{Enabled: true, By: RateLimitByLogger, Rate: 50, Levels: map[LogLevel]RateLimit{LogLevelDebug: {Rate: 5, Burst: 10}}},
+		Redaction: RedactionConfig{Enabled: true, Rules: []RedactionRule{
+			{Keys: []string{"password"}, KeyPatterns: []string{"*_token"}, Action: RedactMask},
+			{ValuePatterns: []string{PatternEmail}},
+		}},
+		OTLP: OTLPConfig{Enabled: true, Protocol: OTLPProtocolGRPC, Endpoint: "http://collector:4317"},
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "logging.yaml",
+			content: `logger:
+  level: info
+  environment: production
+  service_name: file-service
+  levels:
+    db: debug
+  outputs:
+    - type: stdout
+      encoding: console
+    - type: file
+      level: warn
+      path: /var/log/app.log
+      max_size_mb: 100
+      rotate_interval: 24h
+  async:
+    enabled: true
+    flush_interval: 2s
+    overflow: drop_below
+    drop_below: WARN
+  sampling:
+    enabled: true
+    tick: 2s
+    levels:
+      debug:
+        initial: 10
+        thereafter: 1000
+  dedup:
+    enabled: true
+    window: 30s
+  rate_limit:
+    enabled: true
+    by: logger
+    rate: 50
+    levels:
+      DEBUG: {rate: 5, burst: 10}
+  redaction:
+    enabled: true
+    rules:
+      - keys: [password]
+        key_patterns: ["*_token"]
+        action: Mask
+      - value_patterns: [email]
+  otlp:
+    enabled: true
+    protocol: grpc
+    endpoint: http://collector:4317
+`,
+		},
+		{
+			name: "json",
+			file: "logging.json",
+			content: `{
+	"logger": {
+		"level": "INFO",
+		"environment": "production",
+		"service_name": "file-service",
+		"levels": {"db": "DEBUG"},
+		"outputs": [
+			{"type": "stdout", "encoding": "console"},
+			{"type": "file", "level": "WARN", "path": "/var/log/app.log", "max_size_mb": 100, "rotate_interval": "24h"}
+		],
+		"async": {"enabled": true, "flush_interval": "2s", "overflow": "drop_below", "drop_below": "WARN"},
+		"sampling": {"enabled": true, "tick": "2s", "levels": {"DEBUG": {"initial": 10, "thereafter": 1000}}},
+		"dedup": {"enabled": true, "window": "30s"},
+		"rate_limit": {"enabled": true, "by": "logger", "rate": 50, "levels": {"debug": {"rate": 5, "burst": 10}}},
+		"redaction": {"enabled": true, "rules": [
+			{"keys": ["password"], "key_patterns": ["*_token"], "action": "mask"},
+			{"value_patterns": ["email"]}
+		]},
+		"otlp": {"enabled": true, "protocol": "grpc", "endpoint": "http://collector:4317"}
+	}
+}
+`,
+		},
+		{
+			name: "toml",
+			file: "logging.toml",
+			content: `[logger]
+level = "INFO"
+environment = "production"
+service_name = "file-service"
+
+[logger.levels]
+db = "DEBUG"
+
+[[logger.outputs]]
+type = "stdout"
+encoding = "console"
+
+[[logger.outputs]]
+type = "file"
+level = "WARN"
+path = "/var/log/app.log"
+max_size_mb = 100
+rotate_interval = "24h"
+
+[logger.async]
+enabled = true
+flush_interval = "2s"
+overflow = "drop_below"
+drop_below = "WARN"
+
+[logger.sampling]
+enabled = true
+tick = "2s"
+
+[logger.sampling.levels.DEBUG]
+initial = 10
+thereafter = 1000
+
+[logger.dedup]
+enabled = true
+window = "30s"
+
+[logger.rate_limit]
+enabled = true
+by = "logger"
+rate = 50
+levels = { DEBUG = { rate = 5, burst = 10 } }
+
+[logger.redaction]
+enabled = true
+
+[[logger.redaction.rules]]
+keys = ["password"]
+key_patterns = ["*_token"]
+action = "mask"
+
+[[logger.redaction.rules]]
+value_patterns = ["email"]
+
+[logger.otlp]
+enabled = true
+protocol = "grpc"
+endpoint = "http://collector:4317"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			defer clearEnv()
+			os.Setenv("APP_ENV", "production")
+
+			cfg, err := LoadFile(writeConfigFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Logger, want) {
+				t.Errorf("LoadFile() =\n%+v\nwant\n%+v", cfg.Logger, want)
+			}
+		})
+	}
+}
+
+// TestLoadFile_SamplingDisabled tests that sampling disabled in a file is
+// kept off in environments whose preset enables it.
+func TestLoadFile_SamplingDisabled(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	path := writeConfigFile(t, "logging.yaml", `logger:
+  level: INFO
+  environment: production
+  service_name: file-service
+  sampling:
+    enabled: false
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Logger.Sampling.Enabled || !cfg.Logger.Sampling.Disabled {
+		t.Errorf("expected sampling to be disabled, got %+v", cfg.Logger.Sampling)
+	}
+}
+
+// TestLoadFile_EnvOverride tests that environment variables override file values.
+func TestLoadFile_EnvOverride(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("LOG_LEVEL", "DEBUG")
+	os.Setenv("LOG_OUTPUTS", "stderr")
+
+	path := writeConfigFile(t, "logging.yaml", `logger:
+  level: INFO
+  environment: development
+  service_name: file-service
+  outputs:
+    - type: stdout
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Logger.Level != LogLevelDebug || cfg.Logger.Environment != EnvProduction || cfg.Logger.ServiceName != "file-service" {
+		t.Errorf("unexpected config: %+v", cfg.Logger)
+	}
+	if len(cfg.Logger.Outputs) != 1 || cfg.Logger.Outputs[0].Type != OutputStderr {
+		t.Errorf("expected LOG_OUTPUTS to replace the outputs, got %+v", cfg.Logger.Outputs)
+	}
+}
+
+// TestLoadFile_Errors tests that errors mention the location of the bad key.
+func TestLoadFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{
+			name:    "yaml invalid level",
+			file:    "logging.yaml",
+			content: "logger:\n  environment: production\n  service_name: svc\n  level: LOUD\n",
+			want:    "logging.yaml:4: logger.level:",
+		},
+		{
+			name:    "yaml invalid output",
+			file:    "logging.yaml",
+			content: "logger:\n  level: INFO\n  environment: production\n  service_name: svc\n  outputs:\n    - type: stdout\n    - type: tcp\n",
+			want:    "logging.yaml:7: logger.outputs[1].type:",
+		},
+		{
+			name:    "yaml missing service name",
+			file:    "logging.yaml",
+			content: "logger:\n  level: INFO\n  environment: production\n",
+			want:    "logging.yaml:1: logger.service_name:",
+		},
+		{
+			name:    "yaml unknown key",
+			file:    "logging.yaml",
+			content: "logger:\n  level: INFO\n  colour: red\n",
+			want:    "line 3",
+		},
+		{
+			name:    "json invalid duration",
+			file:    "logging.json",
+			content: "{\n  \"logger\": {\n    \"file\": {\n      \"path\": \"app.log\",\n      \"max_age\": \"forever\"\n    }\n  }\n}\n",
+			want:    "logging.json:5: logger.file.max_age:",
+		},
+		{
+			name:    "yaml invalid sampling tick",
+			file:    "logging.yaml",
+			content: "logger:\n  sampling:\n    enabled: true\n    levels:\n      DEBUG:\n        tick: often\n",
+			want:    "logging.yaml:6: logger.sampling.levels.DEBUG.tick:",
+		},
+		{
+			name:    "json syntax error",
+			file:    "logging.json",
+			content: "{\n  \"logger\": {\n    \"level\": INFO\n  }\n}\n",
+			want:    "line 3",
+		},
+		{
+			name:    "toml invalid async",
+			file:    "logging.toml",
+			content: "[logger]\nlevel = \"INFO\"\nenvironment = \"production\"\nservice_name = \"svc\"\n\n[logger.async]\nenabled = true\nbuffer_size = -1\n",
+			want:    "logging.toml:8: logger.async.buffer_size:",
+		},
+		{
+			name:    "toml header comment",
+			file:    "logging.toml",
+			content: "[logger] # main\nlevel = \"INFO\"\nenvironment = \"production\"\nservice_name = \"svc\"\n\n[logger.async] # buffered\nenabled = true\nbuffer_size = -1 # too small\n",
+			want:    "logging.toml:8: logger.async.buffer_size:",
+		},
+		{
+			name:    "toml multi-line values",
+			file:    "logging.toml",
+			content: "[logger]\nlevel = \"INFO\"\nenvironment = \"production\"\nservice_name = \"svc\"\n\n[logger.async]\nenabled = true\nbuffer_size = -1\n\n[[logger.redaction.rules]]\nkeys = [\n  \"password\", # a = b\n  \"token\",\n]\nvalue_patterns = ['''\n[logger.async]\nbuffer_size = 1''']\n",
+			want:    "logging.toml:8: logger.async.buffer_size:",
+		},
+		{
+			name:    "toml invalid sampling initial",
+			file:    "logging.toml",
+			content: "[logger]\nlevel = \"INFO\"\nenvironment = \"production\"\nservice_name = \"svc\"\n\n[logger.sampling]\nenabled = true\ntick = \"1s\"\n\n[logger.sampling.levels.debug]\ntick = \"5s\"\nthereafter = 10\ninitial = -1\n",
+			want:    "logging.toml:13: logger.sampling.levels.DEBUG.initial:",
+		},
+		{
+			name:    "yaml invalid output max age",
+			file:    "logging.yaml",
+			content: "logger:\n  level: INFO\n  environment: production\n  service_name: svc\n  outputs:\n    - type: file\n      path: app.log\n      max_backups: 3\n      max_size_mb: -1\n",
+			want:    "logging.yaml:9: logger.outputs[0].max_size_mb:",
+		},
+		{
+			name:    "json negative dedup window",
+			file:    "logging.json",
+			content: "{\n  \"logger\": {\n    \"level\": \"INFO\",\n    \"service_name\": \"svc\",\n    \"dedup\": {\n      \"enabled\": true,\n      \"window\": \"-1s\"\n    }\n  }\n}\n",
+			want:    "logging.json:7: logger.dedup.window:",
+		},
+		{
+			name:    "toml unknown key",
+			file:    "logging.toml",
+			content: "[logger]\nlevel = \"INFO\"\ncolour = \"red\"\n",
+			want:    "line 3: unknown key 'logger.colour'",
+		},
+		{
+			name:    "unsupported format",
+			file:    "logging.ini",
+			content: "level=INFO\n",
+			want:    "unsupported config file format '.ini'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			defer clearEnv()
+			os.Setenv("APP_ENV", "production")
+
+			_, err := LoadFile(writeConfigFile(t, tt.file, tt.content))
+			if !errors.Is(err, ErrInvalidValue) {
+				t.Fatalf("expected ErrInvalidValue, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error to contain %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import "strings"
+
+// LoadOptions selects the environment variables and .env files read when
+// loading configuration. The zero value reads the standard variables
+// (LOG_LEVEL, APP_ENV, APP_NAME, ...) and the .env file in the working
+// directory, as Load does.
+//
+// Example:
+//
+//	opts := config.LoadOptions{
+//	    Prefix:   "MYSVC_",                                  // MYSVC_LOG_LEVEL, MYSVC_APP_ENV, ...
+//	    Names:    map[string]string{"APP_NAME": "SERVICE"}, // SERVICE instead of MYSVC_APP_NAME
+//	    EnvFiles: []string{".env.local", "config/.env"},
+//	}
+//	cfg, err := opts.Load()
+type LoadOptions struct {
+	// Prefix is prepended to the names of the logger variables, e.g.
+	// "MYSVC_" reads MYSVC_LOG_LEVEL instead of LOG_LEVEL. The standard
+	// OTEL_* variables, which the OTLP exporter also reads, are not prefixed.
+	// Variables of registered domains keep the names of their struct tags.
+	Prefix string
+
+	// Names maps standard variable names, such as "LOG_LEVEL", to the names
+	// read instead. It takes precedence over Prefix.
+	Names map[string]string
+
+	// EnvFiles lists the .env files loaded outside production, instead of
+	// ".env" in the working directory. Missing files are skipped. Variables
+	// already set, by the process environment or an earlier file, are not
+	// overridden.
+	EnvFiles []string
+}
+
+// envName returns the variable read for the standard variable key.
+func (o LoadOptions) envName(key string) string {
+	if name, ok := o.Names[key]; ok {
+		return name
+	}
+	if strings.HasPrefix(key, "OTEL_") {
+		return key
+	}
+	return o.Prefix + key
+}
+
+// lookup returns getenv reading the variables selected by o under their
+// standard names.
+func (o LoadOptions) lookup(getenv getenvFunc) getenvFunc {
+	if o.Prefix == "" && len(o.Names) == 0 {
+		return getenv
+	}
+	return func(key string) string {
+		return getenv(o.envName(key))
+	}
+}
+
+// rename reports errs, keyed by standard variable names, under the names of
+// the variables actually read.
+func (o LoadOptions) rename(errs fieldErrors) fieldErrors {
+	for i := range errs {
+		errs[i].Key = o.envName(errs[i].Key)
+	}
+	return errs
+}
+
+// envFiles returns the .env files to load outside production.
+func (o LoadOptions) envFiles() []string {
+	if len(o.EnvFiles) == 0 {
+		return []string{".env"}
+	}
+	return o.EnvFiles
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestLoadOptions_Load tests reading prefixed and renamed variables.
+func TestLoadOptions_Load(t *testing.T) {
+	opts := LoadOptions{
+		Prefix: "MYSVC_",
+		Names:  map[string]string{"APP_NAME": "MYSVC_SERVICE"},
+	}
+
+	t.Run("reads selected variables", func(t *testing.T) {
+		clearEnv()
+		defer clearEnv()
+		os.Setenv("LOG_LEVEL", "ERROR") // belongs to another tool
+		t.Setenv("MYSVC_LOG_LEVEL", "DEBUG")
+		t.Setenv("MYSVC_APP_ENV", "production")
+		t.Setenv("MYSVC_SERVICE", "my-service")
+		t.Setenv("MYSVC_LOG_OUTPUTS", "stderr")
+
+		cfg, err := opts.Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Logger.Level != LogLevelDebug || cfg.Logger.ServiceName != "my-service" || cfg.Logger.Outputs[0].Type != OutputStderr {
+			t.Errorf("unexpected config: %+v", cfg.Logger)
+		}
+	})
+
+	t.Run("reports selected variables", func(t *testing.T) {
+		clearEnv()
+		defer clearEnv()
+		t.Setenv("MYSVC_APP_ENV", "production")
+		t.Setenv("MYSVC_LOG_ASYNC", "maybe")
+
+		_, err := opts.Load()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		var keys []string
+		for _, fe := range verr.Errors {
+			keys = append(keys, fe.Key)
+		}
+		if want := []string{"MYSVC_LOG_LEVEL", "MYSVC_SERVICE", "MYSVC_LOG_ASYNC"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("expected %v, got %v", want, keys)
+		}
+	})
+}
+
+// TestLoadOptions_EnvFiles tests loading the selected .env files.
+func TestLoadOptions_EnvFiles(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	dir := t.TempDir()
+	local, shared := filepath.Join(dir, ".env.local"), filepath.Join(dir, ".env")
+	if err := os.WriteFile(local, []byte("LOG_LEVEL=DEBUG\n"), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+	if err := os.WriteFile(shared, []byte("LOG_LEVEL=INFO\nAPP_ENV=development\nAPP_NAME=from-file\n"), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+
+	opts := LoadOptions{EnvFiles: []string{local, filepath.Join(dir, "missing.env"), shared}}
+	cfg, err := opts.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Logger.Level != LogLevelDebug || cfg.Logger.ServiceName != "from-file" {
+		t.Errorf("expected earlier files to take precedence, got %+v", cfg.Logger)
+	}
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// RedactAction decides how a sensitive value is rewritten.
+type RedactAction string
+
+const (
+	// RedactReplace replaces the value with "[REDACTED]" (default).
+	RedactReplace RedactAction = "replace"
+	// RedactMask replaces the characters of the value with '*', keeping the
+	// last four of values longer than eight characters.
+	RedactMask RedactAction = "mask"
+	// RedactHash replaces the value with a SHA-256 digest, so that equal
+	// values can still be correlated.
+	RedactHash RedactAction = "hash"
+	// RedactTokenize replaces the value with a token keyed by
+	// LoggerConfig.Tokenization, so that equal values can be correlated
+	// across services sharing the key, and re-identified by holders of the
+	// key only.
+	RedactTokenize RedactAction = "tokenize"
+)
+
+// Validate checks if the action is valid.
+func (a RedactAction) Validate() error {
+	switch a {
+	case RedactReplace, RedactMask, RedactHash, RedactTokenize:
+		return nil
+	default:
+		return fmt.Errorf("%w: redact action must be replace, mask, hash, or tokenize, got '%s'", ErrInvalidValue, a)
+	}
+}
+
+// Built-in value patterns, usable by name in RedactionRule.ValuePatterns.
+const (
+	// PatternEmail matches email addresses.
+	PatternEmail = "email"
+	// PatternCreditCard matches payment card numbers, with or without
+	// separators, that pass the Luhn check.
+	PatternCreditCard = "credit_card"
+	// PatternJWT matches JSON Web Tokens.
+	PatternJWT = "jwt"
+)
+
+// valuePatterns holds the regular expressions of the built-in value patterns.
+var valuePatterns = map[string]string{
+	PatternEmail:      `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	PatternCreditCard: `\b\d(?:[ -]?\d){12,18}\b`,
+	PatternJWT:        `\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,
+}
+
+// ValuePattern returns the regular expression of a value pattern: that of
+// the built-in pattern with this name, or the pattern itself.
+func ValuePattern(pattern string) string {
+	if re, ok := valuePatterns[pattern]; ok {
+		return re
+	}
+	return pattern
+}
+
+// RedactionRule selects sensitive values and how they are rewritten.
+type RedactionRule struct {
+	// Keys are field names whose value is redacted, compared
+	// case-insensitively, e.g. "password".
+	Keys []string
+	// KeyPatterns are glob patterns (see path.Match) of field names whose
+	// value is redacted, compared case-insensitively, e.g. "*_token".
+	KeyPatterns []string
+	// ValuePatterns are regular expressions, or names of built-in patterns
+	// such as PatternEmail. The matching parts of string values and of
+	// messages are redacted.
+	ValuePatterns []string
+	// Action rewrites the selected values. Empty means RedactReplace.
+	Action RedactAction
+}
+
+// Validate checks that the rule selects values and that its patterns compile.
+func (r RedactionRule) Validate() error {
+	if len(r.Keys) == 0 && len(r.KeyPatterns) == 0 && len(r.ValuePatterns) == 0 {
+		return fmt.Errorf("%w: redaction rule needs keys, key patterns or value patterns", ErrInvalidValue)
+	}
+	if r.Action != "" {
+		if err := r.Action.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range r.KeyPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: invalid key pattern '%s': %v", ErrInvalidValue, pattern, err)
+		}
+	}
+	for _, pattern := range r.ValuePatterns {
+		if _, err := regexp.Compile(ValuePattern(pattern)); err != nil {
+			return fmt.Errorf("%w: invalid value pattern '%s': %v", ErrInvalidValue, pattern, err)
+		}
+	}
+	return nil
+}
+
+// DefaultRedactionRule returns the rule applied when redaction is enabled
+// without rules: common credential field names, and email addresses, card
+// numbers and JSON Web Tokens in values, replaced.
+func DefaultRedactionRule() RedactionRule {
+	return RedactionRule{
+		Keys:          []string{"password", "passwd", "secret", "token", "authorization", "api_key", "apikey", "cookie", "set-cookie"},
+		KeyPatterns:   []string{"*password*", "*secret*", "*_token", "*_key"},
+		ValuePatterns: []string{PatternEmail, PatternCreditCard, PatternJWT},
+		Action:        RedactReplace,
+	}
+}
+
+// RedactionConfig defines the redaction of sensitive values before entries
+// are encoded. It applies to the fields of entries, to the fields attached
+// with With, including nested objects and arrays, and to messages for value
+// patterns.
+type RedactionConfig struct {
+	// Enabled turns on redaction.
+	Enabled bool
+	// Rules select the values to redact. A field whose name matches several
+	// rules is rewritten by the first one. Empty uses DefaultRedactionRule.
+	Rules []RedactionRule
+}
+
+// EffectiveRules returns the rules applied: Rules, or the default rule.
+func (c RedactionConfig) EffectiveRules() []RedactionRule {
+	if len(c.Rules) == 0 {
+		return []RedactionRule{DefaultRedactionRule()}
+	}
+	return c.Rules
+}
+
+// tokenizes reports whether a rule uses RedactTokenize.
+func (c RedactionConfig) tokenizes() bool {
+	for _, rule := range c.Rules {
+		if rule.Action == RedactTokenize {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks if the redaction configuration is valid.
+func (c RedactionConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	for i, rule := range c.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("redaction rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// applyRedactionEnv overrides the redaction configuration from environment.
+// The LOG_REDACT_KEYS, LOG_REDACT_KEY_PATTERNS, LOG_REDACT_VALUES and
+// LOG_REDACT_ACTION variables replace the rules with a single rule; those
+// not set keep the settings of the default rule.
+func applyRedactionEnv(cfg *RedactionConfig, r *envReader) {
+	r.setBool("LOG_REDACT", &cfg.Enabled)
+
+	rule, set := DefaultRedactionRule(), false
+	for key, dst := range map[string]*[]string{
+		"LOG_REDACT_KEYS":         &rule.Keys,
+		"LOG_REDACT_KEY_PATTERNS": &rule.KeyPatterns,
+		"LOG_REDACT_VALUES":       &rule.ValuePatterns,
+	} {
+		if value := r.getenv(key); strings.TrimSpace(value) != "" {
+			*dst = splitList(value)
+			set = true
+		}
+	}
+	if value := strings.TrimSpace(r.getenv("LOG_REDACT_ACTION")); value != "" {
+		rule.Action = RedactAction(strings.ToLower(value))
+		set = true
+	}
+	if set {
+		cfg.Rules = []RedactionRule{rule}
+	}
+}
+
+// MinTokenKeyLength is the minimum length in bytes of tokenization keys.
+const MinTokenKeyLength = 16
+
+// keyIDPattern matches valid tokenization key IDs.
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// TokenizationConfig holds the key of the tokens replacing sensitive
+// identifiers: the values of fields built with logger.Token, and those
+// selected by redaction rules with RedactTokenize.
+//
+// Tokens are keyed HMAC-SHA256 digests of the values. Services sharing the
+// key produce the same token for the same value, so that the activity of a
+// user can be followed in logs without the logs holding the user ID. Tokens
+// carry the key ID: after a rotation, the key of a token is found by its ID.
+type TokenizationConfig struct {
+	// KeyID identifies the key in tokens, e.g. "2024-06". It is required
+	// with a key.
+	KeyID string
+
+	// Key is the HMAC key, of at least MinTokenKeyLength bytes.
+	Key string
+
+	// KeyFile is a file holding the key, such as a mounted secret, used
+	// instead of Key. Surrounding whitespace is ignored.
+	KeyFile string
+}
+
+// Enabled reports whether a key is configured.
+func (c TokenizationConfig) Enabled() bool {
+	return c.Key != "" || c.KeyFile != ""
+}
+
+// LoadKey returns the key, reading KeyFile if set.
+func (c TokenizationConfig) LoadKey() ([]byte, error) {
+	key := c.Key
+	if c.KeyFile != "" {
+		data, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot read token key file: %v", ErrInvalidValue, err)
+		}
+		key = strings.TrimSpace(string(data))
+	}
+	if len(key) < MinTokenKeyLength {
+		return nil, fmt.Errorf("%w: token key must be at least %d bytes, got %d", ErrInvalidValue, MinTokenKeyLength, len(key))
+	}
+	return []byte(key), nil
+}
+
+// Validate checks that the key ID is valid and that the key can be loaded.
+func (c TokenizationConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Key != "" && c.KeyFile != "" {
+		return fmt.Errorf("%w: token key and key file are mutually exclusive", ErrInvalidValue)
+	}
+	if !keyIDPattern.MatchString(c.KeyID) {
+		return fmt.Errorf("%w: token key ID must be non-empty and contain only letters, digits, '.', '_' or '-', got '%s'", ErrInvalidValue, c.KeyID)
+	}
+	_, err := c.LoadKey()
+	return err
+}
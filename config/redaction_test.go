@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestRedactionConfig_Validate tests the validation of redaction configuration.
+func TestRedactionConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    RedactionConfig
+		wantError bool
+	}{
+		{name: "disabled ignores rules", config: RedactionConfig{Rules: []RedactionRule{{}}}},
+		{name: "default rule", config: RedactionConfig{Enabled: true}},
+		{name: "keys", config: RedactionConfig{Enabled: true, Rules: []RedactionRule{{Keys: []string{"password"}, Action: RedactHash}}}},
+		{name: "built-in and custom value patterns", config: RedactionConfig{Enabled: true, Rules: []RedactionRule{{ValuePatterns: []string{PatternJWT, `\bssn-\d+\b`}}}}},
+		{name: "empty rule", config: RedactionConfig{Enabled: true, Rules: []RedactionRule{{}}}, wantError: true},
+		{name: "unknown action", config: RedactionConfig{Enabled: true, Rules: []RedactionRule{{Keys: []string{"password"}, Action: "drop"}}}, wantError: true},
+		{name: "invalid key pattern", config: RedactionConfig{Enabled: true, Rules: []RedactionRule{{KeyPatterns: []string{"[token"}}}}, wantError: true},
+		{name: "invalid value pattern", config: RedactionConfig{Enabled: true, Rules: []RedactionRule{{ValuePatterns: []string{"(card"}}}}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestLoad_RedactionConfig tests that redaction settings are loaded from environment.
+func TestLoad_RedactionConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_REDACT", "true")
+
+	t.Run("default rule", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := (RedactionConfig{Enabled: true}); !reflect.DeepEqual(cfg.Logger.Redaction, want) {
+			t.Errorf("expected %+v, got %+v", want, cfg.Logger.Redaction)
+		}
+	})
+
+	t.Run("rule from variables", func(t *testing.T) {
+		os.Setenv("LOG_REDACT_KEYS", "password, ssn")
+		os.Setenv("LOG_REDACT_ACTION", "Hash")
+		defer os.Unsetenv("LOG_REDACT_KEYS")
+		defer os.Unsetenv("LOG_REDACT_ACTION")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := DefaultRedactionRule()
+		want.Keys = []string{"password", "ssn"}
+		want.Action = RedactHash
+		if rules := cfg.Logger.Redaction.Rules; !reflect.DeepEqual(rules, []RedactionRule{want}) {
+			t.Errorf("expected %+v, got %+v", want, rules)
+		}
+	})
+
+	t.Run("reports invalid settings", func(t *testing.T) {
+		os.Setenv("LOG_REDACT_VALUES", "(card")
+		defer os.Unsetenv("LOG_REDACT_VALUES")
+
+		_, err := Load()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		if len(verr.Errors) != 1 || verr.Errors[0].Key != "LOG_REDACT*" {
+			t.Errorf("expected an error for LOG_REDACT*, got %v", verr.Errors)
+		}
+	})
+}
+
+// TestTokenizationConfig_Validate tests the validation of tokenization keys.
+func TestTokenizationConfig_Validate(t *testing.T) {
+	keyFile := writeConfigFile(t, "token-key", "  0123456789abcdef0123456789abcdef\n")
+	shortFile := writeConfigFile(t, "short-key", "short")
+
+	tests := []struct {
+		name      string
+		config    TokenizationConfig
+		wantError bool
+	}{
+		{name: "no key", config: TokenizationConfig{KeyID: "ignored"}},
+		{name: "key", config: TokenizationConfig{KeyID: "2024-06", Key: "0123456789abcdef"}},
+		{name: "key file", config: TokenizationConfig{KeyID: "k1", KeyFile: keyFile}},
+		{name: "missing key ID", config: TokenizationConfig{Key: "0123456789abcdef"}, wantError: true},
+		{name: "key ID with separator", config: TokenizationConfig{KeyID: "k:1", Key: "0123456789abcdef"}, wantError: true},
+		{name: "short key", config: TokenizationConfig{KeyID: "k1", Key: "secret"}, wantError: true},
+		{name: "short key file", config: TokenizationConfig{KeyID: "k1", KeyFile: shortFile}, wantError: true},
+		{name: "missing key file", config: TokenizationConfig{KeyID: "k1", KeyFile: keyFile + ".missing"}, wantError: true},
+		{name: "key and key file", config: TokenizationConfig{KeyID: "k1", Key: "0123456789abcdef", KeyFile: keyFile}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error but got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	key, err := TokenizationConfig{KeyID: "k1", KeyFile: keyFile}.LoadKey()
+	if err != nil || string(key) != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("expected trimmed key from file, got %q, %v", key, err)
+	}
+}
+
+// TestLoad_TokenizationConfig tests that the tokenization key is loaded from
+// environment and required by the tokenize action.
+func TestLoad_TokenizationConfig(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("LOG_LEVEL", "INFO")
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("APP_NAME", "test-service")
+	os.Setenv("LOG_REDACT", "true")
+	os.Setenv("LOG_REDACT_KEYS", "user_id")
+	os.Setenv("LOG_REDACT_ACTION", "tokenize")
+
+	t.Run("requires a key", func(t *testing.T) {
+		_, err := Load()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		if len(verr.Errors) != 1 || verr.Errors[0].Key != "LOG_REDACT*" {
+			t.Errorf("expected an error for LOG_REDACT*, got %v", verr.Errors)
+		}
+	})
+
+	t.Run("key from variables", func(t *testing.T) {
+		os.Setenv("LOG_TOKEN_KEY", "0123456789abcdef")
+		os.Setenv("LOG_TOKEN_KEY_ID", "2024-06")
+		defer os.Unsetenv("LOG_TOKEN_KEY")
+		defer os.Unsetenv("LOG_TOKEN_KEY_ID")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := (TokenizationConfig{KeyID: "2024-06", Key: "0123456789abcdef"}); cfg.Logger.Tokenization != want {
+			t.Errorf("expected %+v, got %+v", want, cfg.Logger.Tokenization)
+		}
+	})
+
+	t.Run("reports invalid key", func(t *testing.T) {
+		os.Setenv("LOG_TOKEN_KEY_FILE", "/nonexistent/token-key")
+		os.Setenv("LOG_TOKEN_KEY_ID", "2024-06")
+		defer os.Unsetenv("LOG_TOKEN_KEY_FILE")
+		defer os.Unsetenv("LOG_TOKEN_KEY_ID")
+
+		_, err := Load()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		if len(verr.Errors) != 1 || verr.Errors[0].Key != "LOG_TOKEN_KEY*" {
+			t.Errorf("expected an error for LOG_TOKEN_KEY*, got %v", verr.Errors)
+		}
+	})
+}
+
+// TestLoadFile_Tokenization tests reading the tokenization section of
+// configuration files.
+func TestLoadFile_Tokenization(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	os.Setenv("APP_ENV", "production")
+
+	keyFile := writeConfigFile(t, "token-key", "0123456789abcdef")
+	path := writeConfigFile(t, "logging.yaml", `logger:
+  level: info
+  service_name: file-service
+  tokenization:
+    key_id: k1
+    key_file: `+keyFile+`
+`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (TokenizationConfig{KeyID: "k1", KeyFile: keyFile}); cfg.Logger.Tokenization != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.Logger.Tokenization)
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// FieldError is the failure of a single configuration value.
+type FieldError struct {
+	// Key identifies the value: an environment variable such as "LOG_LEVEL",
+	// or the location and key of a configuration file value such as
+	// "logging.yaml:4: logger.level".
+	Key string
+	// Err describes the failure. It wraps ErrMissingRequiredEnvVar or
+	// ErrInvalidValue.
+	Err error
+}
+
+// Error returns the key followed by the failure.
+func (e FieldError) Error() string {
+	return e.Key + ": " + e.Err.Error()
+}
+
+// Unwrap returns the failure, so that errors.Is matches its sentinel error.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError reports every missing or invalid value found while loading
+// or validating a configuration, in the order they were checked.
+//
+// It matches ErrMissingRequiredEnvVar and ErrInvalidValue with errors.Is if
+// any of its values does.
+//
+// Example:
+//
+//	var verr *config.ValidationError
+//	if errors.As(err, &verr) {
+//	    for _, fe := range verr.Errors {
+//	        fmt.Printf("fix %s: %v\n", fe.Key, fe.Err)
+//	    }
+//	}
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error lists the failures on a single line.
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("%d configuration errors: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the failures.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Table renders the failures as an aligned two-column table, one per line,
+// for printing at startup:
+//
+//	KEY        PROBLEM
+//	LOG_LEVEL  required environment variable is not set
+//	APP_ENV    invalid configuration value: environment must be one of 'development', 'production', 'staging', 'test', got 'prod'
+func (e *ValidationError) Table() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tPROBLEM")
+	for _, fe := range e.Errors {
+		fmt.Fprintf(w, "%s\t%v\n", fe.Key, fe.Err)
+	}
+	_ = w.Flush()
+	return b.String()
+}
+
+// fieldErrors accumulates the failures of a configuration.
+type fieldErrors []FieldError
+
+// check records err, if not nil, as the failure of key.
+func (e *fieldErrors) check(key string, err error) {
+	if err != nil {
+		*e = append(*e, FieldError{Key: key, Err: err})
+	}
+}
+
+// has reports whether a failure of key was recorded.
+func (e fieldErrors) has(key string) bool {
+	for _, fe := range e {
+		if fe.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// err returns the failures as a *ValidationError, or nil if there are none.
+func (e fieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: e}
+}
+
+// keyError is the failure of the value at key within a configuration
+// section, so that configuration files can point at the line of that key
+// rather than of the section.
+type keyError struct {
+	key string
+	err error
+}
+
+// atKey marks err, if not nil, as the failure of key within the section
+// being validated. Marks nest: atKey("levels.WARN", atKey("initial", err))
+// is the failure of "levels.WARN.initial".
+func atKey(key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &keyError{key: key, err: err}
+}
+
+// Error returns the failure, without the key.
+func (e *keyError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the failure.
+func (e *keyError) Unwrap() error {
+	return e.err
+}
+
+// subkey returns the key within its section at which err occurred, as marked
+// with atKey, or "" if it was not marked.
+func subkey(err error) string {
+	var kerr *keyError
+	if !errors.As(err, &kerr) {
+		return ""
+	}
+	if rest := subkey(kerr.err); rest != "" {
+		return kerr.key + "." + rest
+	}
+	return kerr.key
+}
+
+// envKeys maps the configuration file keys used by validateFields to the
+// environment variables that set them. Sections set by several variables
+// map to their common prefix.
+var envKeys = map[string]string{
+	"level":        "LOG_LEVEL",
+	"environment":  "APP_ENV",
+	"service_name": "APP_NAME",
+	"levels":       "LOG_LEVELS",
+	"outputs":      "LOG_OUTPUTS",
+	"file":         "LOG_FILE*",
+	"async":        "LOG_ASYNC*",
+	"sampling":     "LOG_SAMPLING*",
+	"dedup":        "LOG_DEDUP*",
+	"rate_limit":   "LOG_RATE_LIMIT*",
+	"redaction":    "LOG_REDACT*",
+	"tokenization": "LOG_TOKEN_KEY*",
+	"trace":        "LOG_TRACE_*",
+	"otlp":         "LOG_OTLP_LEVEL/OTEL_*",
+}
+
+// envKey returns the environment variable for a configuration file key such
+// as "outputs[1]" or "levels.db".
+func envKey(key string) string {
+	if i := strings.IndexAny(key, ".["); i >= 0 {
+		key = key[:i]
+	}
+	if env, ok := envKeys[key]; ok {
+		return env
+	}
+	return key
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoad_AggregatesErrors tests that every missing or invalid variable is reported.
+func TestLoad_AggregatesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		wantKeys []string
+		wantIs   []error
+	}{
+		{
+			name:     "all required variables missing",
+			envVars:  map[string]string{"APP_ENV": "production"},
+			wantKeys: []string{"LOG_LEVEL", "APP_NAME"},
+			wantIs:   []error{ErrMissingRequiredEnvVar},
+		},
+		{
+			name: "missing and invalid variables",
+			envVars: map[string]string{
+				"APP_ENV":              "production",
+				"APP_NAME":             "svc",
+				"LOG_FILE_MAX_SIZE_MB": "big",
+				"LOG_OUTPUTS":          "stdout,tcp",
+				"LOG_LEVELS":           "db=LOUD",
+			},
+			wantKeys: []string{"LOG_LEVEL", "LOG_FILE_MAX_SIZE_MB", "LOG_OUTPUTS", "LOG_LEVELS"},
+			wantIs:   []error{ErrMissingRequiredEnvVar, ErrInvalidValue},
+		},
+		{
+			name: "invalid values only",
+			envVars: map[string]string{
+				"LOG_LEVEL": "LOUD",
+				"APP_ENV":   "prod",
+				"APP_NAME":  "svc",
+			},
+			wantKeys: []string{"LOG_LEVEL", "APP_ENV"},
+			wantIs:   []error{ErrInvalidValue},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			defer clearEnv()
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			_, err := Load()
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *ValidationError, got %v", err)
+			}
+
+			var keys []string
+			for _, fe := range verr.Errors {
+				keys = append(keys, fe.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			for _, target := range tt.wantIs {
+				if !errors.Is(err, target) {
+					t.Errorf("expected errors.Is(err, %v)", target)
+				}
+			}
+		})
+	}
+}
+
+// TestLoadFile_AggregatesErrors tests that file values and environment
+// overrides are reported together.
+func TestLoadFile_AggregatesErrors(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("LOG_ASYNC_BUFFER_SIZE", "many")
+
+	path := writeConfigFile(t, "logging.yaml", "logger:\n  level: LOUD\n  service_name: svc\n  file:\n    path: app.log\n    max_age: forever\n")
+	_, err := LoadFile(path)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+
+	want := []string{path + ":6: logger.file.max_age", "LOG_ASYNC_BUFFER_SIZE", path + ":2: logger.level"}
+	var keys []string
+	for _, fe := range verr.Errors {
+		keys = append(keys, fe.Key)
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+}
+
+// TestLoggerConfig_ValidateAll tests that Validate reports every invalid field by environment variable.
+func TestLoggerConfig_ValidateAll(t *testing.T) {
+	cfg := LoggerConfig{
+		Level:       LogLevelInfo,
+		Environment: "prod",
+		Async:       AsyncConfig{Enabled: true, BufferSize: -1},
+		Outputs:     []OutputConfig{{Type: OutputStdout}, {Type: OutputNetwork}},
+	}
+
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+
+	want := []string{"APP_ENV", "APP_NAME", "LOG_ASYNC*", "LOG_OUTPUTS"}
+	var keys []string
+	for _, fe := range verr.Errors {
+		keys = append(keys, fe.Key)
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+}
+
+// TestValidationError tests the message and table rendering.
+func TestValidationError(t *testing.T) {
+	single := &ValidationError{Errors: []FieldError{
+		{Key: "LOG_LEVEL", Err: ErrMissingRequiredEnvVar},
+	}}
+	if got, want := single.Error(), "LOG_LEVEL: required environment variable is not set"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	multi := &ValidationError{Errors: []FieldError{
+		{Key: "LOG_LEVEL", Err: ErrMissingRequiredEnvVar},
+		{Key: "APP_ENV", Err: ErrInvalidValue},
+	}}
+	if got := multi.Error(); !strings.HasPrefix(got, "2 configuration errors: LOG_LEVEL: ") || !strings.Contains(got, "; APP_ENV: ") {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	want := "KEY        PROBLEM\n" +
+		"LOG_LEVEL  required environment variable is not set\n" +
+		"APP_ENV    invalid configuration value\n"
+	if got := multi.Table(); got != want {
+		t.Errorf("Table() =\n%s\nwant\n%s", got, want)
+	}
+}
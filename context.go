@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// loggerKey is the context key under which NewContext stores a logger.
+type loggerKey struct{}
+
+// fieldsKey is the context key under which ContextWithFields stores fields.
+type fieldsKey struct{}
+
+// nopLogger is returned by FromContext when no logger is available.
+var nopLogger = &Logger{Logger: zap.NewNop()}
+
+// NewContext returns a copy of ctx carrying l.
+//
+// Example:
+//
+//	ctx = logger.NewContext(ctx, logger.With(zap.String("request_id", id)))
+//	handle(ctx)
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext.
+//
+// If ctx carries no logger, the global logger is returned; if the global
+// logger has not been initialized either, a no-op logger is returned, so the
+// result is always safe to use. Fields stored with ContextWithFields are not
+// attached; use the Ctx logging functions or ContextFields for that.
+//
+// Example:
+//
+//	func handle(ctx context.Context) {
+//	    log := logger.FromContext(ctx)
+//	    log.Info("handling request")
+//	}
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	if l, err := TryGet(); err == nil {
+		return l
+	}
+	return nopLogger
+}
+
+// ContextWithFields returns a copy of ctx carrying fields in addition to any
+// fields already stored in ctx.
+//
+// The fields are added to every entry logged through the Ctx functions, such
+// as InfoCtx, without creating a derived logger.
+//
+// Example:
+//
+//	ctx = logger.ContextWithFields(ctx, zap.String("tenant", tenant))
+//	logger.InfoCtx(ctx, "tenant resolved")
+func ContextWithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	existing := ContextFields(ctx)
+	merged := make([]zap.Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// ContextFields returns the fields stored in ctx by ContextWithFields.
+func ContextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	return fields
+}
+
+// contextFields prepends the fields carried by ctx to fields: the span
+// context of an active OpenTelemetry span, then the fields stored by
+// ContextWithFields.
+func (l *Logger) contextFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	span := l.traceFields(ctx)
+	stored := ContextFields(ctx)
+	if len(span) == 0 && len(stored) == 0 {
+		return fields
+	}
+	merged := make([]zap.Field, 0, len(span)+len(stored)+len(fields))
+	merged = append(merged, span...)
+	merged = append(merged, stored...)
+	return append(merged, fields...)
+}
+
+// DebugCtx logs a message at the DEBUG level using the logger from ctx,
+// including the fields stored in ctx and the active span's trace context.
+func DebugCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	log := FromContext(ctx)
+	log.callerSkipped().Debug(msg, log.contextFields(ctx, fields)...)
+}
+
+// InfoCtx logs a message at the INFO level using the logger from ctx,
+// including the fields stored in ctx and the active span's trace context.
+//
+// Example:
+//
+//	logger.InfoCtx(ctx, "order placed", zap.String("order_id", id))
+func InfoCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	log := FromContext(ctx)
+	log.callerSkipped().Info(msg, log.contextFields(ctx, fields)...)
+}
+
+// WarnCtx logs a message at the WARN level using the logger from ctx,
+// including the fields stored in ctx and the active span's trace context.
+func WarnCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	log := FromContext(ctx)
+	log.callerSkipped().Warn(msg, log.contextFields(ctx, fields)...)
+}
+
+// ErrorCtx logs a message at the ERROR level using the logger from ctx,
+// including the fields stored in ctx and the active span's trace context.
+func ErrorCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	log := FromContext(ctx)
+	log.callerSkipped().Error(msg, log.contextFields(ctx, fields)...)
+}
+
+// FatalCtx logs a message at the FATAL level using the logger from ctx,
+// including the fields stored in ctx and the active span's trace context,
+// and terminates the application.
+//
+// Use this sparingly—prefer returning errors whenever possible.
+func FatalCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	log := FromContext(ctx)
+	log.callerSkipped().Fatal(msg, log.contextFields(ctx, fields)...)
+}
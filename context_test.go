@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newObservedLogger returns a logger recording entries, configured like one
+// built by this package.
+func newObservedLogger() (*Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return &Logger{Logger: zap.New(core, zap.AddCaller())}, logs
+}
+
+// TestFromContext tests logger retrieval and fallbacks.
+func TestFromContext(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	stored, _ := newObservedLogger()
+
+	if got := FromContext(context.Background()); got != nopLogger {
+		t.Error("expected no-op logger without context logger or global logger")
+	}
+	if got := FromContext(nil); got != nopLogger {
+		t.Error("expected no-op logger for nil context")
+	}
+
+	if err := InitWithDefaults(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	if got := FromContext(context.Background()); got != Get() {
+		t.Error("expected global logger without context logger")
+	}
+
+	ctx := NewContext(context.Background(), stored)
+	if got := FromContext(ctx); got != stored {
+		t.Error("expected logger stored in context")
+	}
+}
+
+// TestContextWithFields tests that fields accumulate without affecting the parent context.
+func TestContextWithFields(t *testing.T) {
+	parent := ContextWithFields(context.Background(), zap.String("a", "1"))
+	child := ContextWithFields(parent, zap.String("b", "2"))
+
+	if got := len(ContextFields(parent)); got != 1 {
+		t.Errorf("expected 1 field on parent, got %d", got)
+	}
+	fields := ContextFields(child)
+	if len(fields) != 2 || fields[0].Key != "a" || fields[1].Key != "b" {
+		t.Errorf("unexpected child fields: %v", fields)
+	}
+	if ContextFields(context.Background()) != nil {
+		t.Error("expected no fields on empty context")
+	}
+}
+
+// TestCtxFunctions tests the context-aware logging functions.
+func TestCtxFunctions(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   func(ctx context.Context, msg string, fields ...zap.Field)
+		level zapcore.Level
+	}{
+		{name: "DebugCtx", log: DebugCtx, level: zapcore.DebugLevel},
+		{name: "InfoCtx", log: InfoCtx, level: zapcore.InfoLevel},
+		{name: "WarnCtx", log: WarnCtx, level: zapcore.WarnLevel},
+		{name: "ErrorCtx", log: ErrorCtx, level: zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, logs := newObservedLogger()
+			ctx := NewContext(context.Background(), log)
+			ctx = ContextWithFields(ctx, zap.String("request_id", "abc"))
+
+			tt.log(ctx, "hello", zap.Int("attempt", 2))
+
+			entries := logs.All()
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 entry, got %d", len(entries))
+			}
+			entry := entries[0]
+			if entry.Level != tt.level || entry.Message != "hello" {
+				t.Errorf("unexpected entry: %v %q", entry.Level, entry.Message)
+			}
+			fields := entry.ContextMap()
+			if fields["request_id"] != "abc" || fields["attempt"] != int64(2) {
+				t.Errorf("unexpected fields: %v", fields)
+			}
+			if !strings.HasSuffix(entry.Caller.File, "context_test.go") {
+				t.Errorf("expected caller in context_test.go, got %s", entry.Caller.File)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// dedupCore suppresses entries identical to one written less than a window
+// ago, and writes a summary of the suppressed entries when the window
+// closes or on Sync.
+//
+// Entries are identical when they have the same level, logger name,
+// message, fields and context fields. Entries above ERROR are never
+// suppressed.
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+	// context fingerprints the fields added with With.
+	context string
+}
+
+// dedupState holds the recent entries of the cores derived from the same
+// root, keyed by fingerprint.
+type dedupState struct {
+	window     time.Duration
+	suppressed *atomic.Uint64
+
+	mu        sync.Mutex
+	seen      map[string]*dedupRecord
+	lastSweep time.Time
+}
+
+// dedupRecord tracks an entry written and the identical entries suppressed
+// after it.
+type dedupRecord struct {
+	// core writes the summary, with the context fields of the entry.
+	core zapcore.Core
+	// entry and fields are those of the entry written, its fields encoded
+	// when it was written.
+	entry       zapcore.Entry
+	fields      []zapcore.Field
+	first, last time.Time
+	repeats     int
+	timer       *time.Timer
+}
+
+// newDedupCore wraps core with the deduplication of cfg, counting the
+// entries it suppresses in suppressed.
+func newDedupCore(core zapcore.Core, cfg config.DedupConfig, suppressed *atomic.Uint64) zapcore.Core {
+	state := &dedupState{
+		window:     cfg.WithDefaults().Window,
+		suppressed: suppressed,
+		seen:       make(map[string]*dedupRecord),
+	}
+	return &dedupCore{Core: core, state: state}
+}
+
+// With adds structured context to the wrapped core and to the fingerprint
+// of the entries.
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{
+		Core:    c.Core.With(fields),
+		state:   c.state,
+		context: c.context + encodeFingerprint(fields),
+	}
+}
+
+// Check adds the core to ce if the wrapped core is enabled for the entry.
+// The wrapped core is checked again on Write, once the fields are known.
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write writes the entry unless an identical one was written less than a
+// window ago.
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level > zapcore.ErrorLevel {
+		return writeChecked(c.Core, ent, fields)
+	}
+
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%s%s", ent.Level, ent.LoggerName, ent.Message, c.context, encodeFingerprint(fields))
+	s := c.state
+
+	s.mu.Lock()
+	rec, ok := s.seen[key]
+	if ok && ent.Time.Before(rec.first.Add(s.window)) {
+		rec.last = ent.Time
+		rec.repeats++
+		if rec.timer == nil {
+			rec.timer = time.AfterFunc(time.Until(rec.first.Add(s.window)), func() { s.expire(key, rec) })
+		}
+		s.mu.Unlock()
+		s.suppressed.Add(1)
+		return nil
+	}
+	if ok {
+		delete(s.seen, key)
+	}
+	s.sweep(ent.Time)
+	s.seen[key] = &dedupRecord{core: c.Core, entry: ent, fields: snapshotFields(fields), first: ent.Time}
+	s.mu.Unlock()
+
+	var err error
+	if ok {
+		err = rec.summarize()
+	}
+	return errors.Join(err, writeChecked(c.Core, ent, fields))
+}
+
+// Sync writes the summaries of the suppressed entries and flushes the
+// wrapped core.
+func (c *dedupCore) Sync() error {
+	return errors.Join(c.state.flush(), c.Core.Sync())
+}
+
+// expire closes the window of rec, if it is still current.
+func (s *dedupState) expire(key string, rec *dedupRecord) {
+	s.mu.Lock()
+	if s.seen[key] != rec {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.seen, key)
+	s.mu.Unlock()
+
+	_ = rec.summarize()
+}
+
+// flush closes the windows of the entries that have suppressed repeats,
+// returning the errors of writing their summaries.
+func (s *dedupState) flush() error {
+	var pending []*dedupRecord
+	s.mu.Lock()
+	for key, rec := range s.seen {
+		if rec.repeats > 0 {
+			delete(s.seen, key)
+			pending = append(pending, rec)
+		}
+	}
+	s.mu.Unlock()
+
+	var err error
+	for _, rec := range pending {
+		err = errors.Join(err, rec.summarize())
+	}
+	return err
+}
+
+// sweep forgets, at most once per window, the entries whose window closed
+// without repeats. Entries with repeats are removed by their timer. The
+// caller must hold s.mu.
+func (s *dedupState) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.window {
+		return
+	}
+	s.lastSweep = now
+	for key, rec := range s.seen {
+		if rec.repeats == 0 && !now.Before(rec.first.Add(s.window)) {
+			delete(s.seen, key)
+		}
+	}
+}
+
+// summarize writes an entry reporting the repeats suppressed by rec, if any.
+// rec must no longer be in the state.
+func (rec *dedupRecord) summarize() error {
+	if rec.timer != nil {
+		rec.timer.Stop()
+	}
+	if rec.repeats == 0 {
+		return nil
+	}
+
+	ent := rec.entry
+	times := "times"
+	if rec.repeats == 1 {
+		times = "time"
+	}
+	ent.Message = fmt.Sprintf("%s (message repeated %d %s)", ent.Message, rec.repeats, times)
+	ent.Time = time.Now()
+	ent.Stack = ""
+	fields := append(rec.fields[:len(rec.fields):len(rec.fields)],
+		zap.Int("repeated", rec.repeats),
+		zap.Time("first_seen", rec.first),
+		zap.Time("last_seen", rec.last),
+	)
+	return writeChecked(rec.core, ent, fields)
+}
+
+// writeChecked writes the entry to core if core accepts it, so that cores
+// deciding in Check, such as samplers, are honored. It returns the errors of
+// the cores that wrote the entry.
+func writeChecked(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) error {
+	ce := core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	var errs writeErrors
+	ce.ErrorOutput = &errs
+	ce.Write(fields...)
+	return errs.err
+}
+
+// writeErrors is the error output of the entries written by writeChecked.
+// A CheckedEntry only reports write errors by printing them there, so they
+// are parsed back.
+type writeErrors struct {
+	err error
+}
+
+// Write records the write error printed in p.
+func (w *writeErrors) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	if _, after, ok := strings.Cut(msg, " write error: "); ok {
+		msg = after
+	}
+	w.err = errors.Join(w.err, errors.New(msg))
+	return len(p), nil
+}
+
+// Sync implements zapcore.WriteSyncer.
+func (w *writeErrors) Sync() error {
+	return nil
+}
+
+// snapshotFields returns fields with the values evaluated lazily, such as
+// those of Stringer, Object and Any fields, encoded now, so that a summary
+// written later reports the values logged.
+func snapshotFields(fields []zapcore.Field) []zapcore.Field {
+	snapshot := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		switch f.Type {
+		case zapcore.StringerType, zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType,
+			zapcore.ReflectType, zapcore.ErrorType, zapcore.InlineMarshalerType:
+			enc := zapcore.NewMapObjectEncoder()
+			f.AddTo(enc)
+			keys := make([]string, 0, len(enc.Fields))
+			for key := range enc.Fields {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				snapshot = append(snapshot, zap.Any(key, enc.Fields[key]))
+			}
+		default:
+			snapshot = append(snapshot, f)
+		}
+	}
+	return snapshot
+}
+
+// encodeFingerprint returns a deterministic representation of fields.
+func encodeFingerprint(fields []zapcore.Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return fmt.Sprintf("%v", enc.Fields)
+}
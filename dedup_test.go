@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newDedupLogger returns a logger deduplicating entries within window and
+// the entries it writes.
+func newDedupLogger(window time.Duration) (*zap.Logger, *observer.ObservedLogs, *atomic.Uint64) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	var suppressed atomic.Uint64
+	core := newDedupCore(observed, config.DedupConfig{Enabled: true, Window: window}, &suppressed)
+	return zap.New(core), logs, &suppressed
+}
+
+// TestDedupCore tests that identical entries are collapsed into a summary on Sync.
+func TestDedupCore(t *testing.T) {
+	log, logs, suppressed := newDedupLogger(time.Hour)
+	child := log.With(zap.String("dependency", "db"))
+
+	for i := 0; i < 5; i++ {
+		child.Warn("connection refused", zap.Int("port", 5432))
+		child.Warn("connection refused", zap.Int("port", 5433))
+		log.Warn("connection refused", zap.Int("port", 5432))
+		log.DPanic("never suppressed")
+	}
+	if got := logs.FilterMessage("connection refused").Len(); got != 3 {
+		t.Errorf("expected one entry per distinct fingerprint, got %d", got)
+	}
+	if got := logs.FilterMessage("never suppressed").Len(); got != 5 {
+		t.Errorf("expected entries above ERROR to be kept, got %d", got)
+	}
+	if got := suppressed.Load(); got != 12 {
+		t.Errorf("expected 12 suppressed entries, got %d", got)
+	}
+
+	if err := log.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	summaries := logs.FilterMessageSnippet("(message repeated 4 times)").All()
+	if len(summaries) != 3 {
+		t.Fatalf("expected 3 summaries, got %d: %v", len(summaries), logs.All())
+	}
+	for _, s := range summaries {
+		fields := s.ContextMap()
+		if s.Level != zapcore.WarnLevel || fields["repeated"] != int64(4) || fields["port"] == nil {
+			t.Errorf("unexpected summary %+v", s)
+		}
+		first, last := fields["first_seen"].(time.Time), fields["last_seen"].(time.Time)
+		if first.IsZero() || last.Before(first) {
+			t.Errorf("unexpected timestamps %v, %v", first, last)
+		}
+	}
+	if got := logs.FilterField(zap.String("dependency", "db")).FilterMessageSnippet("repeated").Len(); got != 2 {
+		t.Errorf("expected summaries to keep context fields, got %d", got)
+	}
+
+	// After the summary, the next entry starts a new window.
+	log.Warn("connection refused", zap.Int("port", 5432))
+	if got := logs.FilterMessage("connection refused").Len(); got != 4 {
+		t.Errorf("expected a new window after Sync, got %d entries", got)
+	}
+
+	log.Warn("connection refused", zap.Int("port", 5432))
+	if err := log.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := logs.FilterMessage("connection refused (message repeated 1 time)").Len(); got != 1 {
+		t.Errorf("expected a singular summary for a single repeat, got %v", logs.FilterMessageSnippet("repeated 1").All())
+	}
+}
+
+// mutableState is a fmt.Stringer whose value changes after it is logged.
+type mutableState struct {
+	value string
+}
+
+func (s *mutableState) String() string { return s.value }
+
+// TestDedupCore_SummarySnapshot tests that summaries report the fields and
+// caller of the entry written, not their values when the summary is written.
+func TestDedupCore_SummarySnapshot(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	var suppressed atomic.Uint64
+	log := zap.New(newDedupCore(observed, config.DedupConfig{Enabled: true, Window: time.Hour}, &suppressed), zap.AddCaller())
+
+	state := &mutableState{value: "open"}
+	log.Warn("circuit", zap.Stringer("state", state), zap.Any("attempts", map[string]int{"db": 1}))
+	first := logs.All()[0].Caller
+	log.Warn("circuit", zap.Stringer("state", state), zap.Any("attempts", map[string]int{"db": 1}))
+	state.value = "closed"
+
+	if err := log.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	summaries := logs.FilterMessageSnippet("(message repeated 1 time)").All()
+	if len(summaries) != 1 {
+		t.Fatalf("expected a summary, got %v", logs.All())
+	}
+	summary := summaries[0]
+	if got := summary.ContextMap()["state"]; got != "open" {
+		t.Errorf("expected the value logged, got %v", got)
+	}
+	if got := summary.ContextMap()["attempts"]; got == nil {
+		t.Errorf("expected Any fields to be kept, got %v", summary.ContextMap())
+	}
+	if summary.Caller != first {
+		t.Errorf("expected the caller of the entry written %v, got %v", first, summary.Caller)
+	}
+}
+
+// TestDedupCore_Window tests that the summary is written when the window closes.
+func TestDedupCore_Window(t *testing.T) {
+	log, logs, _ := newDedupLogger(20 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		log.Error("retrying")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for logs.FilterMessageSnippet("repeated 2 times").Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a summary when the window closes, got %v", logs.All())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	log.Error("retrying")
+	if got := logs.FilterMessage("retrying").Len(); got != 2 {
+		t.Errorf("expected a new window after the summary, got %d entries", got)
+	}
+}
+
+// TestBuildLogger_Dedup tests that deduplication from the configuration is
+// applied and reported in Stats.
+func TestBuildLogger_Dedup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+		Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+		Dedup:       config.DedupConfig{Enabled: true, Window: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		log.Named("client").Error("upstream unavailable", zap.String("upstream", "billing"))
+	}
+	if got := log.Stats().DedupSuppressed; got != 9 {
+		t.Errorf("expected 9 suppressed entries, got %d", got)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("expected the entry and its summary, got %d", len(lines))
+	}
+	for _, s := range []string{`"message":"upstream unavailable (message repeated 9 times)"`, `"repeated":9`, `"logger":"client"`, `"service":"test-service"`} {
+		if !strings.Contains(lines[1], s) {
+			t.Errorf("expected %s in summary: %s", s, lines[1])
+		}
+	}
+}
+
+// TestBuildLogger_DedupRateLimit tests that repeats suppressed by
+// deduplication do not use up the rate limit of their call site.
+func TestBuildLogger_DedupRateLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+		Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+		Dedup:       config.DedupConfig{Enabled: true, Window: time.Hour},
+		RateLimit:   config.RateLimitConfig{Enabled: true, Rate: 0.001, Burst: 2},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		log.Warn("connection refused")
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 2 || !strings.Contains(lines[1], "(message repeated 9 times)") {
+		t.Fatalf("expected the entry and a summary of 9 repeats, got %v", lines)
+	}
+	stats := log.Stats()
+	if stats.DedupSuppressed != 9 || stats.RateLimited != 0 {
+		t.Errorf("expected 9 suppressed and no rate limited entries, got %+v", stats)
+	}
+}
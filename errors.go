@@ -1,6 +1,10 @@
 package logger
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/gath-stack/gologger/config"
+)
 
 // Sentinel errors that can be checked with errors.Is().
 //
@@ -33,7 +37,8 @@ var (
 	ErrInvalidLogLevel = errors.New("invalid log level")
 
 	// ErrInvalidEnvironment is returned when an invalid environment is provided.
-	// Valid environments are: development, production.
+	// Valid environments are: development, test, staging, production, and
+	// those added with config.RegisterEnvironment.
 	ErrInvalidEnvironment = errors.New("invalid environment")
 
 	// ErrMissingServiceName is returned when service name is empty or contains only whitespace.
@@ -42,4 +47,16 @@ var (
 	// ErrSyncFailed is returned when log synchronization fails.
 	// This may occur when flushing buffered log entries to the underlying writer.
 	ErrSyncFailed = errors.New("failed to sync logger")
+
+	// ErrLevelNotAdjustable is returned when changing the level of a logger that
+	// was not built by this package and therefore has no adjustable level.
+	ErrLevelNotAdjustable = errors.New("logger level is not adjustable")
+
+	// ErrMissingRequiredEnvVar is matched by configuration errors when a
+	// required environment variable is not set.
+	ErrMissingRequiredEnvVar = config.ErrMissingRequiredEnvVar
+
+	// ErrInvalidValue is matched by configuration errors when a variable or
+	// configuration file value is invalid.
+	ErrInvalidValue = config.ErrInvalidValue
 )
@@ -0,0 +1,369 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+)
+
+// backupTimeFormat is the timestamp embedded in rotated file names.
+// It sorts lexically and contains no characters that are invalid in file names.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// fileSink is a zapcore.WriteSyncer writing to a file with size- and
+// time-based rotation.
+//
+// Rotated files are renamed to "<name>-<timestamp><ext>", or
+// "<name>-<timestamp>-<n><ext>" within the same millisecond, next to the
+// active file and optionally gzipped; old backups are pruned according to MaxBackups
+// and MaxAge. The file is reopened on SIGHUP so that external tools such as
+// logrotate can move it away.
+type fileSink struct {
+	cfg config.FileConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	signals chan os.Signal
+	mill    chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newFileSink opens the configured file, creating parent directories as needed.
+func newFileSink(cfg config.FileConfig) (*fileSink, error) {
+	s := &fileSink{
+		cfg:     cfg,
+		now:     time.Now,
+		signals: make(chan os.Signal, 1),
+		mill:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	signal.Notify(s.signals, syscall.SIGHUP)
+	s.wg.Add(1)
+	go s.run()
+
+	return s, nil
+}
+
+// run reopens the file on SIGHUP and prunes backups after rotation.
+func (s *fileSink) run() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.signals:
+			_ = s.Reopen()
+		case <-s.mill:
+			s.millBackups()
+		case <-s.done:
+			select {
+			case <-s.mill:
+				s.millBackups()
+			default:
+			}
+			return
+		}
+	}
+}
+
+// open opens the configured path for appending. Callers must hold s.mu or
+// otherwise have exclusive access.
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	s.file = file
+	s.size = info.Size()
+	s.openedAt = s.now()
+	return nil
+}
+
+// Write writes p to the file, rotating first if p would exceed the size limit
+// or the rotation interval has elapsed.
+func (s *fileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, os.ErrClosed
+	}
+
+	// A failed rotation is reported, but the entry is still written to the
+	// reopened file.
+	var rotateErr error
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return 0, err
+		}
+	} else if s.shouldRotate(int64(len(p))) {
+		rotateErr = s.rotate()
+		if s.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// shouldRotate reports whether writing n more bytes requires a rotation.
+func (s *fileSink) shouldRotate(n int64) bool {
+	if s.cfg.MaxSizeMB > 0 && s.size > 0 && s.size+n > int64(s.cfg.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	if s.cfg.RotateInterval > 0 && s.now().Sub(s.openedAt) >= s.cfg.RotateInterval {
+		return true
+	}
+	return false
+}
+
+// rotate renames the active file to a backup and opens a fresh one.
+// The active file is reopened even if closing or renaming it fails, so that
+// logging goes on; s.file is nil only if it cannot be reopened, in which
+// case the next write retries. Callers must hold s.mu.
+func (s *fileSink) rotate() error {
+	var errs []error
+	if err := s.file.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close log file: %w", err))
+	}
+	s.file = nil
+	if err := os.Rename(s.cfg.Path, s.backupName(s.now())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, fmt.Errorf("failed to rotate log file: %w", err))
+	}
+	if err := s.open(); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	select {
+	case s.mill <- struct{}{}:
+	default:
+	}
+	return errors.Join(errs...)
+}
+
+// backupName returns an unused name for a backup rotated at t. Backups
+// rotated within the same millisecond get a counter suffix
+// ("app-<timestamp>-1.log").
+func (s *fileSink) backupName(t time.Time) string {
+	dir, prefix, ext := s.backupParts()
+	stamp := prefix + t.UTC().Format(backupTimeFormat)
+	name := filepath.Join(dir, stamp+ext)
+	for i := 1; backupExists(name); i++ {
+		name = filepath.Join(dir, fmt.Sprintf("%s-%d%s", stamp, i, ext))
+	}
+	return name
+}
+
+// backupExists reports whether a backup, possibly compressed, exists at path.
+func backupExists(path string) bool {
+	for _, p := range []string{path, path + ".gz"} {
+		if _, err := os.Lstat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// backupParts splits the configured path into the directory, the backup
+// name prefix ("app-") and the extension (".log").
+func (s *fileSink) backupParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(s.cfg.Path)
+	base := filepath.Base(s.cfg.Path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backup is a rotated file found on disk.
+type backup struct {
+	path      string
+	rotatedAt time.Time
+	// seq is the counter suffix of backups rotated within the same
+	// millisecond, 0 for the first one.
+	seq int
+}
+
+// listBackups returns the rotated files of this sink, newest first.
+func (s *fileSink) listBackups() ([]backup, error) {
+	dir, prefix, ext := s.backupParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		stamp = strings.TrimSuffix(stamp, ext)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+		rotatedAt, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)])
+		if err != nil {
+			continue
+		}
+		seq := 0
+		if suffix := stamp[len(backupTimeFormat):]; suffix != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(suffix, "-"))
+			if err != nil || n <= 0 || !strings.HasPrefix(suffix, "-") {
+				continue
+			}
+			seq = n
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), rotatedAt: rotatedAt, seq: seq})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].rotatedAt.Equal(backups[j].rotatedAt) {
+			return backups[i].rotatedAt.After(backups[j].rotatedAt)
+		}
+		return backups[i].seq > backups[j].seq
+	})
+	return backups, nil
+}
+
+// millBackups compresses and prunes rotated files. Errors are ignored: a
+// failure to clean up must never interrupt logging.
+func (s *fileSink) millBackups() {
+	backups, err := s.listBackups()
+	if err != nil {
+		return
+	}
+
+	cutoff := s.now().Add(-s.cfg.MaxAge)
+	for i, b := range backups {
+		expired := s.cfg.MaxAge > 0 && b.rotatedAt.Before(cutoff)
+		excess := s.cfg.MaxBackups > 0 && i >= s.cfg.MaxBackups
+		if expired || excess {
+			_ = os.Remove(b.path)
+			continue
+		}
+		if s.cfg.Compress && !strings.HasSuffix(b.path, ".gz") {
+			_ = compressFile(b.path)
+		}
+	}
+}
+
+// compressFile gzips path into path+".gz" and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Sync commits the file contents to stable storage.
+func (s *fileSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.file == nil {
+		return nil
+	}
+	return s.file.Sync()
+}
+
+// Reopen closes and reopens the file at the configured path.
+//
+// This is triggered by SIGHUP, after an external tool has moved the file.
+func (s *fileSink) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	var closeErr error
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			closeErr = fmt.Errorf("failed to close log file: %w", err)
+		}
+		s.file = nil
+	}
+	if err := s.open(); err != nil {
+		return errors.Join(closeErr, err)
+	}
+	return closeErr
+}
+
+// Close stops signal handling and closes the file. Subsequent writes fail
+// with os.ErrClosed.
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	signal.Stop(s.signals)
+	close(s.done)
+	var err error
+	if s.file != nil {
+		err = s.file.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
@@ -0,0 +1,394 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+)
+
+// fakeClock is a controllable time source for rotation tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestFileSink opens a sink in a temporary directory driven by a fake clock.
+func newTestFileSink(t *testing.T, cfg config.FileConfig) (*fileSink, *fakeClock) {
+	t.Helper()
+	if cfg.Path == "" {
+		cfg.Path = filepath.Join(t.TempDir(), "app.log")
+	}
+	clock := &fakeClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)}
+
+	s, err := newFileSink(cfg)
+	if err != nil {
+		t.Fatalf("failed to open file sink: %v", err)
+	}
+	s.mu.Lock()
+	s.now = clock.Now
+	s.openedAt = clock.Now()
+	s.mu.Unlock()
+	t.Cleanup(func() { _ = s.Close() })
+	return s, clock
+}
+
+// listLogFiles returns the base names of all files in dir.
+func listLogFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+// waitForFiles polls dir until it holds want files or the deadline expires.
+func waitForFiles(t *testing.T, dir string, want int) []string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		names := listLogFiles(t, dir)
+		if len(names) == want || time.Now().After(deadline) {
+			return names
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestFileSink_Write tests that entries are appended to the file.
+func TestFileSink_Write(t *testing.T) {
+	s, _ := newTestFileSink(t, config.FileConfig{})
+
+	if _, err := s.Write([]byte("first\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Write([]byte("second\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(s.cfg.Path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("unexpected file contents: %q", data)
+	}
+}
+
+// TestFileSink_RotateBySize tests size-based rotation and backup pruning.
+func TestFileSink_RotateBySize(t *testing.T) {
+	s, clock := newTestFileSink(t, config.FileConfig{MaxSizeMB: 1, MaxBackups: 2})
+	dir := filepath.Dir(s.cfg.Path)
+
+	chunk := []byte(strings.Repeat("x", 600*1024) + "\n")
+	for i := 0; i < 5; i++ {
+		if _, err := s.Write(chunk); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clock.Advance(time.Second)
+	}
+
+	// Active file plus two backups.
+	names := waitForFiles(t, dir, 3)
+	if len(names) != 3 {
+		t.Fatalf("expected active file and 2 backups, got %v", names)
+	}
+	for _, name := range names {
+		if name != "app.log" && !strings.HasPrefix(name, "app-2025-01-15T10-30-") {
+			t.Errorf("unexpected file %q", name)
+		}
+	}
+}
+
+// TestFileSink_RotateSameMillisecond tests that backups rotated at the same
+// time get distinct names.
+func TestFileSink_RotateSameMillisecond(t *testing.T) {
+	s, _ := newTestFileSink(t, config.FileConfig{MaxSizeMB: 1})
+	dir := filepath.Dir(s.cfg.Path)
+
+	for i := 0; i < 3; i++ {
+		chunk := []byte(strings.Repeat(string(rune('a'+i)), 600*1024) + "\n")
+		if _, err := s.Write(chunk); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	names := listLogFiles(t, dir)
+	want := []string{"app-2025-01-15T10-30-00.000-1.log", "app-2025-01-15T10-30-00.000.log", "app.log"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	backups, err := s.listBackups()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backups) != 2 || filepath.Base(backups[0].path) != want[0] {
+		t.Errorf("expected the latest backup first, got %+v", backups)
+	}
+	for i, name := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if wantByte := "bac"[i]; data[0] != wantByte {
+			t.Errorf("%s: expected %q entries, got %q", name, wantByte, data[0])
+		}
+	}
+}
+
+// TestFileSink_RotateError tests that writes go on after a failed rotation.
+func TestFileSink_RotateError(t *testing.T) {
+	s, clock := newTestFileSink(t, config.FileConfig{RotateInterval: time.Hour})
+
+	// Closing the file underneath the sink makes the rotation fail to close it.
+	s.mu.Lock()
+	_ = s.file.Close()
+	s.mu.Unlock()
+	clock.Advance(time.Hour)
+
+	if _, err := s.Write([]byte("first\n")); err == nil {
+		t.Error("expected the rotation error to be reported")
+	}
+	if _, err := s.Write([]byte("second\n")); err != nil {
+		t.Fatalf("expected writes to go on, got %v", err)
+	}
+
+	data, err := os.ReadFile(s.cfg.Path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("unexpected file contents: %q", data)
+	}
+}
+
+// TestFileSink_RotateByInterval tests time-based rotation.
+func TestFileSink_RotateByInterval(t *testing.T) {
+	s, clock := newTestFileSink(t, config.FileConfig{RotateInterval: time.Hour})
+	dir := filepath.Dir(s.cfg.Path)
+
+	if _, err := s.Write([]byte("before\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.Advance(59 * time.Minute)
+	if _, err := s.Write([]byte("still before\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := listLogFiles(t, dir); len(names) != 1 {
+		t.Fatalf("expected no rotation yet, got %v", names)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := s.Write([]byte("after\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := listLogFiles(t, dir)
+	if len(names) != 2 {
+		t.Fatalf("expected rotation, got %v", names)
+	}
+	data, err := os.ReadFile(s.cfg.Path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if string(data) != "after\n" {
+		t.Errorf("unexpected active file contents: %q", data)
+	}
+}
+
+// TestFileSink_MaxAge tests that expired backups are removed.
+func TestFileSink_MaxAge(t *testing.T) {
+	s, clock := newTestFileSink(t, config.FileConfig{RotateInterval: time.Hour, MaxAge: 90 * time.Minute})
+	dir := filepath.Dir(s.cfg.Path)
+
+	for i := 0; i < 4; i++ {
+		if i > 0 {
+			clock.Advance(time.Hour)
+		}
+		if _, err := s.Write([]byte("entry\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Backups rotated at +1h, +2h and +3h; at +3h only those within 90 minutes survive.
+	names := waitForFiles(t, dir, 3)
+	if len(names) != 3 {
+		t.Fatalf("expected active file and 2 recent backups, got %v", names)
+	}
+}
+
+// TestFileSink_Compress tests gzip compression of rotated files.
+func TestFileSink_Compress(t *testing.T) {
+	s, clock := newTestFileSink(t, config.FileConfig{RotateInterval: time.Minute, Compress: true})
+	dir := filepath.Dir(s.cfg.Path)
+
+	if _, err := s.Write([]byte("rotated entry\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if _, err := s.Write([]byte("current entry\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var compressed string
+	for _, name := range listLogFiles(t, dir) {
+		if strings.HasSuffix(name, ".log.gz") {
+			compressed = filepath.Join(dir, name)
+		}
+	}
+	if compressed == "" {
+		t.Fatalf("expected compressed backup, got %v", listLogFiles(t, dir))
+	}
+
+	f, err := os.Open(compressed)
+	if err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("invalid gzip: %v", err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to decompress: %v", err)
+	}
+	if string(data) != "rotated entry\n" {
+		t.Errorf("unexpected backup contents: %q", data)
+	}
+}
+
+// TestFileSink_Reopen tests reopening after the file was moved externally.
+func TestFileSink_Reopen(t *testing.T) {
+	s, _ := newTestFileSink(t, config.FileConfig{})
+	moved := s.cfg.Path + ".1"
+
+	if _, err := s.Write([]byte("old\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.Rename(s.cfg.Path, moved); err != nil {
+		t.Fatalf("failed to move log file: %v", err)
+	}
+
+	if err := s.Reopen(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := s.Write([]byte("new\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(s.cfg.Path)
+	if err != nil {
+		t.Fatalf("expected file to be reopened: %v", err)
+	}
+	if string(data) != "new\n" {
+		t.Errorf("unexpected reopened file contents: %q", data)
+	}
+}
+
+// TestFileSink_Close tests that writes fail after Close.
+func TestFileSink_Close(t *testing.T) {
+	s, _ := newTestFileSink(t, config.FileConfig{})
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("second Close should be a no-op, got: %v", err)
+	}
+	if _, err := s.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected os.ErrClosed but got: %v", err)
+	}
+}
+
+// TestBuildLogger_File tests that the logger writes JSON entries to the file.
+func TestBuildLogger_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvDevelopment,
+		ServiceName: "file-service",
+		File:        config.FileConfig{Path: path},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	log.Info("written to file")
+	log.Debug("filtered out")
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	defer f.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("file entry is not JSON: %q", scanner.Text())
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0]["message"] != "written to file" || entries[0]["service"] != "file-service" {
+		t.Errorf("unexpected entry: %v", entries[0])
+	}
+}
+
+// TestBuildLogger_FileError tests that unusable file paths are reported.
+func TestBuildLogger_FileError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "blocker"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	_, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvDevelopment,
+		ServiceName: "file-service",
+		File:        config.FileConfig{Path: filepath.Join(dir, "blocker", "app.log")},
+	})
+	if err == nil {
+		t.Error("expected error but got nil")
+	}
+}
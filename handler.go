@@ -0,0 +1,245 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// levelHandler serves and changes the level of a logger over HTTP.
+type levelHandler struct {
+	resolve func() (*Logger, error)
+
+	mu       sync.Mutex
+	timer    *time.Timer
+	revertTo LogLevel
+	revertAt time.Time
+	// revertSet reports whether the logger had a level of its own before the
+	// timed change; otherwise the revert removes the override it set.
+	revertSet bool
+}
+
+// levelRequest is the body accepted by PUT and POST requests.
+type levelRequest struct {
+	Level    string `json:"level"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// levelResponse is the body returned by successful requests.
+type levelResponse struct {
+	Level    LogLevel   `json:"level"`
+	RevertTo LogLevel   `json:"revert_to,omitempty"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+// errorResponse is the body returned by failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// LevelHandler returns an http.Handler that reads and changes the level of the
+// global logger.
+//
+// The global logger is resolved on every request, and again when a timed
+// change reverts, so the handler can be mounted before the logger is
+// initialized and keeps working after ReplaceGlobal or Reconfigure. Requests made while the logger is not initialized receive
+// 503 Service Unavailable.
+//
+// See (*Logger).LevelHandler for the protocol.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/admin/log-level", logger.LevelHandler())
+func LevelHandler() http.Handler {
+	return &levelHandler{resolve: TryGet}
+}
+
+// LevelHandler returns an http.Handler that reads and changes the level of
+// this logger and of every logger sharing its level.
+//
+// GET returns the current level:
+//
+//	{"level":"INFO"}
+//
+// PUT and POST change the level. The new level is read from a JSON body or
+// from form values, and must be one of DEBUG, INFO, WARN or ERROR
+// (case-insensitive). An optional duration, in time.ParseDuration format,
+// reverts the level automatically once it elapses:
+//
+//	curl -X PUT -H 'Content-Type: application/json' -d '{"level":"DEBUG","duration":"15m"}' localhost:8080/admin/log-level
+//	curl -X PUT -d 'level=DEBUG&duration=15m' localhost:8080/admin/log-level
+//
+// Changing the level again cancels a pending revert; if the new change also
+// has a duration, it reverts to the level that was in effect before the
+// first timed change. On a named logger that inherited its level, the revert
+// removes the override instead, so that it inherits again.
+func (l *Logger) LevelHandler() http.Handler {
+	return &levelHandler{resolve: func() (*Logger, error) { return l, nil }}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log, err := h.resolve()
+	if err != nil {
+		writeLevelError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.writeLevel(w, log)
+	case http.MethodPut, http.MethodPost:
+		req, err := decodeLevelRequest(r)
+		if err != nil {
+			writeLevelError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := h.apply(log, req); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrLevelNotAdjustable) {
+				status = http.StatusInternalServerError
+			}
+			writeLevelError(w, status, err)
+			return
+		}
+		h.writeLevel(w, log)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		writeLevelError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// apply validates the request and changes the level, scheduling a revert if
+// a duration was given.
+func (h *levelHandler) apply(log *Logger, req levelRequest) error {
+	level := LogLevel(strings.ToUpper(strings.TrimSpace(req.Level)))
+	if err := level.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLogLevel, err)
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", req.Duration, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid duration %q: must be positive", req.Duration)
+		}
+		duration = d
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous, set := log.GetLevel(), true
+	if override, ok := log.levelOverride(); ok {
+		previous = override
+	} else {
+		set = false
+	}
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+		previous, set = h.revertTo, h.revertSet
+	}
+
+	if err := log.SetLevel(level); err != nil {
+		return err
+	}
+
+	if duration > 0 {
+		h.revertTo, h.revertSet = previous, set
+		h.revertAt = time.Now().Add(duration)
+		var timer *time.Timer
+		timer = time.AfterFunc(duration, func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.timer != timer {
+				return
+			}
+			h.timer = nil
+			// Resolve the logger again: the global logger may have been
+			// replaced since the change.
+			log, err := h.resolve()
+			if err != nil {
+				return
+			}
+			if set {
+				_ = log.SetLevel(previous)
+			} else {
+				_ = log.clearLevel()
+			}
+		})
+		h.timer = timer
+	}
+
+	return nil
+}
+
+// writeLevel writes the current level and any pending revert.
+func (h *levelHandler) writeLevel(w http.ResponseWriter, log *Logger) {
+	resp := levelResponse{Level: log.GetLevel()}
+
+	h.mu.Lock()
+	if h.timer != nil {
+		revertAt := h.revertAt
+		resp.RevertTo = h.revertTo
+		resp.RevertAt = &revertAt
+	}
+	h.mu.Unlock()
+
+	writeLevelJSON(w, http.StatusOK, resp)
+}
+
+// maxLevelRequestSize bounds the body of level requests.
+const maxLevelRequestSize = 1 << 16
+
+// decodeLevelRequest reads a level request from a JSON body or form values.
+// A body starting with '{' is read as JSON whatever its content type, since
+// clients such as curl -d send JSON as form data by default.
+func decodeLevelRequest(r *http.Request) (levelRequest, error) {
+	var req levelRequest
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxLevelRequestSize))
+	if err != nil {
+		return req, fmt.Errorf("invalid request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	looksJSON := bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+	if mediaType == "application/json" || looksJSON || (mediaType == "" && r.URL.Query().Get("level") == "") {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return req, fmt.Errorf("invalid request body: %w", err)
+		}
+		return req, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return req, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Level = r.Form.Get("level")
+	req.Duration = r.Form.Get("duration")
+	return req, nil
+}
+
+// writeLevelError writes err as a JSON error response.
+func writeLevelError(w http.ResponseWriter, status int, err error) {
+	writeLevelJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeLevelJSON writes v as a JSON response with the given status.
+func writeLevelJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,281 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+)
+
+// newTestLevelLogger builds a logger at INFO for handler tests.
+func newTestLevelLogger(t *testing.T) *Logger {
+	t.Helper()
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+	return log
+}
+
+// serveLevel performs a request against handler and decodes the JSON response.
+func serveLevel(t *testing.T, handler http.Handler, method, contentType, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/log-level", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+// TestLogger_LevelHandler tests reading and changing the level over HTTP.
+func TestLogger_LevelHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantLevel   LogLevel
+	}{
+		{
+			name:       "get current level",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantLevel:  LogLevelInfo,
+		},
+		{
+			name:        "put JSON level",
+			method:      http.MethodPut,
+			contentType: "application/json",
+			body:        `{"level":"DEBUG"}`,
+			wantStatus:  http.StatusOK,
+			wantLevel:   LogLevelDebug,
+		},
+		{
+			name:        "put JSON level sent as form",
+			method:      http.MethodPut,
+			contentType: "application/x-www-form-urlencoded",
+			body:        `{"level":"debug"}`,
+			wantStatus:  http.StatusOK,
+			wantLevel:   LogLevelDebug,
+		},
+		{
+			name:        "post form level",
+			method:      http.MethodPost,
+			contentType: "application/x-www-form-urlencoded",
+			body:        "level=warn",
+			wantStatus:  http.StatusOK,
+			wantLevel:   LogLevelWarn,
+		},
+		{
+			name:        "invalid level",
+			method:      http.MethodPut,
+			contentType: "application/json",
+			body:        `{"level":"TRACE"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantLevel:   LogLevelInfo,
+		},
+		{
+			name:        "invalid duration",
+			method:      http.MethodPut,
+			contentType: "application/json",
+			body:        `{"level":"DEBUG","duration":"soon"}`,
+			wantStatus:  http.StatusBadRequest,
+			wantLevel:   LogLevelInfo,
+		},
+		{
+			name:        "malformed body",
+			method:      http.MethodPut,
+			contentType: "application/json",
+			body:        `{`,
+			wantStatus:  http.StatusBadRequest,
+			wantLevel:   LogLevelInfo,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodDelete,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantLevel:  LogLevelInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newTestLevelLogger(t)
+			handler := log.LevelHandler()
+
+			status, resp := serveLevel(t, handler, tt.method, tt.contentType, tt.body)
+
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d (response: %v)", status, tt.wantStatus, resp)
+			}
+			if status == http.StatusOK && resp["level"] != string(tt.wantLevel) {
+				t.Errorf("response level = %v, want %v", resp["level"], tt.wantLevel)
+			}
+			if status != http.StatusOK && resp["error"] == nil {
+				t.Error("expected error message in response")
+			}
+			if got := log.GetLevel(); got != tt.wantLevel {
+				t.Errorf("logger level = %v, want %v", got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+// TestLogger_LevelHandler_Revert tests that timed level changes expire.
+func TestLogger_LevelHandler_Revert(t *testing.T) {
+	log := newTestLevelLogger(t)
+	handler := log.LevelHandler()
+
+	status, resp := serveLevel(t, handler, http.MethodPut, "application/json", `{"level":"DEBUG","duration":"50ms"}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, response: %v", status, resp)
+	}
+	if resp["revert_to"] != string(LogLevelInfo) {
+		t.Errorf("revert_to = %v, want %v", resp["revert_to"], LogLevelInfo)
+	}
+
+	// A second timed change keeps the original revert target.
+	status, resp = serveLevel(t, handler, http.MethodPut, "application/json", `{"level":"WARN","duration":"50ms"}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, response: %v", status, resp)
+	}
+	if resp["revert_to"] != string(LogLevelInfo) {
+		t.Errorf("revert_to = %v, want %v", resp["revert_to"], LogLevelInfo)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for log.GetLevel() != LogLevelInfo && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := log.GetLevel(); got != LogLevelInfo {
+		t.Errorf("level after revert = %v, want %v", got, LogLevelInfo)
+	}
+
+	// A permanent change cancels the pending revert.
+	serveLevel(t, handler, http.MethodPut, "application/json", `{"level":"DEBUG","duration":"50ms"}`)
+	serveLevel(t, handler, http.MethodPut, "application/json", `{"level":"ERROR"}`)
+	time.Sleep(100 * time.Millisecond)
+	if got := log.GetLevel(); got != LogLevelError {
+		t.Errorf("level after permanent change = %v, want %v", got, LogLevelError)
+	}
+}
+
+// waitForLevel waits for log to reach level, as a revert does.
+func waitForLevel(t *testing.T, log *Logger, level LogLevel) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for log.GetLevel() != level && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := log.GetLevel(); got != level {
+		t.Errorf("level after revert = %v, want %v", got, level)
+	}
+}
+
+// TestLogger_LevelHandler_RevertNamed tests that reverting a timed change of
+// a named logger without a level of its own removes the override it set.
+func TestLogger_LevelHandler_RevertNamed(t *testing.T) {
+	log := newTestLevelLogger(t)
+	db := log.Named("db")
+
+	status, resp := serveLevel(t, db.LevelHandler(), http.MethodPut, "application/json", `{"level":"DEBUG","duration":"50ms"}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, response: %v", status, resp)
+	}
+	waitForLevel(t, db, LogLevelInfo)
+
+	if err := log.SetLevel(LogLevelWarn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := db.GetLevel(); got != LogLevelWarn {
+		t.Errorf("expected the named logger to inherit the root level again, got %v", got)
+	}
+}
+
+// TestLevelHandler_RevertReplaced tests that a timed change of the global
+// level is reverted on the global logger in place when the timer fires.
+func TestLevelHandler_RevertReplaced(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	if err := InitGlobal(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+	}); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+
+	status, resp := serveLevel(t, LevelHandler(), http.MethodPut, "application/json", `{"level":"DEBUG","duration":"50ms"}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, response: %v", status, resp)
+	}
+
+	replacement := newTestLevelLogger(t)
+	if err := replacement.SetLevel(LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ReplaceGlobal(replacement)
+	waitForLevel(t, replacement, LogLevelInfo)
+}
+
+// TestLevelHandler tests the handler bound to the global logger.
+func TestLevelHandler(t *testing.T) {
+	t.Run("returns 503 when not initialized", func(t *testing.T) {
+		resetGlobalLogger()
+
+		status, resp := serveLevel(t, LevelHandler(), http.MethodGet, "", "")
+		if status != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want %d", status, http.StatusServiceUnavailable)
+		}
+		if resp["error"] != ErrNotInitialized.Error() {
+			t.Errorf("error = %v, want %v", resp["error"], ErrNotInitialized)
+		}
+	})
+
+	t.Run("changes global level", func(t *testing.T) {
+		resetGlobalLogger()
+		defer resetGlobalLogger()
+
+		if err := InitWithDefaults(); err != nil {
+			t.Fatalf("failed to initialize: %v", err)
+		}
+
+		status, _ := serveLevel(t, LevelHandler(), http.MethodPut, "application/json", `{"level":"ERROR"}`)
+		if status != http.StatusOK {
+			t.Errorf("status = %d, want %d", status, http.StatusOK)
+		}
+		if got := GetLevel(); got != LogLevelError {
+			t.Errorf("GetLevel() = %v, want %v", got, LogLevelError)
+		}
+	})
+}
+
+// TestLevelHandler_NotAdjustable tests loggers without an adjustable level.
+func TestLevelHandler_NotAdjustable(t *testing.T) {
+	log := &Logger{Logger: newTestLevelLogger(t).Logger}
+
+	status, resp := serveLevel(t, log.LevelHandler(), http.MethodPut, "application/json", `{"level":"DEBUG"}`)
+	if status != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", status, http.StatusInternalServerError)
+	}
+	if !strings.Contains(resp["error"].(string), ErrLevelNotAdjustable.Error()) {
+		t.Errorf("unexpected error: %v", resp["error"])
+	}
+}
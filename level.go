@@ -0,0 +1,340 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// toZapLevel converts a configuration log level into its zap equivalent.
+func toZapLevel(level config.LogLevel) (zapcore.Level, error) {
+	switch level {
+	case config.LogLevelDebug:
+		return zapcore.DebugLevel, nil
+	case config.LogLevelInfo:
+		return zapcore.InfoLevel, nil
+	case config.LogLevelWarn:
+		return zapcore.WarnLevel, nil
+	case config.LogLevelError:
+		return zapcore.ErrorLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("%w: %s", ErrInvalidLogLevel, level)
+	}
+}
+
+// fromZapLevel converts a zap level back into the configuration vocabulary.
+// Levels above ERROR (DPANIC, PANIC, FATAL) are reported as ERROR.
+func fromZapLevel(level zapcore.Level) config.LogLevel {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return config.LogLevelDebug
+	case level == zapcore.InfoLevel:
+		return config.LogLevelInfo
+	case level == zapcore.WarnLevel:
+		return config.LogLevelWarn
+	default:
+		return config.LogLevelError
+	}
+}
+
+// levelRegistry holds the root level and the per-logger overrides shared by
+// every logger derived from the same root.
+type levelRegistry struct {
+	root zap.AtomicLevel
+
+	// mu serializes writers; readers load overrides without locking.
+	mu        sync.Mutex
+	overrides atomic.Pointer[map[string]zapcore.Level]
+}
+
+// newLevelRegistry creates a registry with the given root level and overrides.
+func newLevelRegistry(root zapcore.Level, overrides map[string]config.LogLevel) (*levelRegistry, error) {
+	m, err := toZapLevels(overrides)
+	if err != nil {
+		return nil, err
+	}
+	r := &levelRegistry{root: zap.NewAtomicLevelAt(root)}
+	r.overrides.Store(&m)
+	return r, nil
+}
+
+// toZapLevels converts per-logger level overrides into their zap equivalents.
+func toZapLevels(overrides map[string]config.LogLevel) (map[string]zapcore.Level, error) {
+	m := make(map[string]zapcore.Level, len(overrides))
+	for name, level := range overrides {
+		zapLevel, err := toZapLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("logger '%s': %w", name, err)
+		}
+		m[name] = zapLevel
+	}
+	return m, nil
+}
+
+// levelFor returns the effective level of the named logger: its own override,
+// else the override of its closest ancestor ("db" for "db.pool"), else the
+// root level.
+func (r *levelRegistry) levelFor(name string) zapcore.Level {
+	if overrides := *r.overrides.Load(); len(overrides) > 0 {
+		for name != "" {
+			if level, ok := overrides[name]; ok {
+				return level
+			}
+			i := strings.LastIndexByte(name, '.')
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+	}
+	return r.root.Level()
+}
+
+// setLevel sets the level of the named logger, or the root level if name is empty.
+func (r *levelRegistry) setLevel(name string, level zapcore.Level) {
+	if name == "" {
+		r.root.SetLevel(level)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := *r.overrides.Load()
+	updated := make(map[string]zapcore.Level, len(current)+1)
+	for n, l := range current {
+		updated[n] = l
+	}
+	updated[name] = level
+	r.overrides.Store(&updated)
+}
+
+// override returns the level set for the named logger itself, and whether
+// one is set. The root logger, named "", always has one.
+func (r *levelRegistry) override(name string) (zapcore.Level, bool) {
+	if name == "" {
+		return r.root.Level(), true
+	}
+	level, ok := (*r.overrides.Load())[name]
+	return level, ok
+}
+
+// clearLevel removes the override of the named logger, which then inherits
+// the level of its closest ancestor again. The root level is kept.
+func (r *levelRegistry) clearLevel(name string) {
+	if name == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := *r.overrides.Load()
+	if _, ok := current[name]; !ok {
+		return
+	}
+	updated := make(map[string]zapcore.Level, len(current))
+	for n, l := range current {
+		if n != name {
+			updated[n] = l
+		}
+	}
+	r.overrides.Store(&updated)
+}
+
+// replace sets the root level and replaces all overrides, discarding changes
+// made with setLevel.
+func (r *levelRegistry) replace(root zapcore.Level, overrides map[string]zapcore.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides.Store(&overrides)
+	r.root.SetLevel(root)
+}
+
+// enabler returns a LevelEnabler tracking the effective level of the named logger.
+func (r *levelRegistry) enabler(name string) zapcore.LevelEnabler {
+	return namedLevel{registry: r, name: name}
+}
+
+// namedLevel is the LevelEnabler of a single named logger.
+type namedLevel struct {
+	registry *levelRegistry
+	name     string
+}
+
+// Enabled reports whether lvl is at or above the effective level.
+func (n namedLevel) Enabled(lvl zapcore.Level) bool {
+	return lvl >= n.registry.levelFor(n.name)
+}
+
+// Level reports the effective level, for zapcore.LevelOf.
+func (n namedLevel) Level() zapcore.Level {
+	return n.registry.levelFor(n.name)
+}
+
+// levelFilterCore gates an arbitrary core behind a (possibly dynamic) level.
+//
+// It is used to make cores supplied through WithCore honor the level shared
+// by every logger derived from the same root.
+type levelFilterCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+// newLevelFilterCore wraps core so that entries below level are discarded.
+func newLevelFilterCore(core zapcore.Core, level zapcore.LevelEnabler) zapcore.Core {
+	return &levelFilterCore{Core: core, level: level}
+}
+
+// unwrapLevelFilter returns the core wrapped by a levelFilterCore, or core
+// itself if it is not filtered.
+func unwrapLevelFilter(core zapcore.Core) zapcore.Core {
+	if f, ok := core.(*levelFilterCore); ok {
+		return f.Core
+	}
+	return core
+}
+
+// Enabled reports whether both the shared level and the wrapped core accept lvl.
+func (c *levelFilterCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl) && c.Core.Enabled(lvl)
+}
+
+// Level reports the minimum enabled level, for zapcore.LevelOf.
+func (c *levelFilterCore) Level() zapcore.Level {
+	level := zapcore.LevelOf(c.level)
+	if inner := zapcore.LevelOf(c.Core); inner > level {
+		return inner
+	}
+	return level
+}
+
+// With adds structured context to the wrapped core, keeping the filter.
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check delegates to the wrapped core if the shared level accepts the entry.
+func (c *levelFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// SetLevel changes the minimum level of this logger at runtime.
+//
+// The level is shared by every logger derived from this one through With,
+// WithCore or WithOTELCore, so changing it on any of them affects all. On a
+// named logger (see Named), SetLevel overrides the level for that name and
+// its descendants only; on the root logger it changes the default level.
+//
+// Example:
+//
+//	if err := log.SetLevel(logger.LogLevelDebug); err != nil {
+//	    return err
+//	}
+func (l *Logger) SetLevel(level LogLevel) error {
+	if l.levels == nil {
+		return ErrLevelNotAdjustable
+	}
+	zapLevel, err := toZapLevel(level)
+	if err != nil {
+		return err
+	}
+	l.levels.setLevel(l.Logger.Name(), zapLevel)
+	return nil
+}
+
+// GetLevel returns the current effective minimum level of this logger.
+func (l *Logger) GetLevel() LogLevel {
+	if l.levels == nil {
+		return fromZapLevel(zapcore.LevelOf(l.Logger.Core()))
+	}
+	return fromZapLevel(l.levels.levelFor(l.Logger.Name()))
+}
+
+// levelOverride returns the level set for this logger itself, and whether
+// one is set rather than inherited from an ancestor.
+func (l *Logger) levelOverride() (LogLevel, bool) {
+	if l.levels == nil {
+		return l.GetLevel(), true
+	}
+	level, ok := l.levels.override(l.Logger.Name())
+	return fromZapLevel(level), ok
+}
+
+// clearLevel removes the level set for this named logger, so that it
+// inherits the level of its closest ancestor again.
+func (l *Logger) clearLevel() error {
+	if l.levels == nil {
+		return ErrLevelNotAdjustable
+	}
+	l.levels.clearLevel(l.Logger.Name())
+	return nil
+}
+
+// Named returns a child logger whose name is appended to this logger's name
+// with a period, e.g. log.Named("db").Named("pool") is named "db.pool".
+//
+// The name is emitted in the "logger" field of every entry. Named loggers
+// honor per-logger level overrides configured through LoggerConfig.Levels
+// (LOG_LEVELS="db=DEBUG,http=WARN"): a logger uses its own override, else
+// the override of its closest ancestor, else the root level.
+//
+// Example:
+//
+//	dbLog := logger.Get().Named("db")
+//	dbLog.Debug("query executed", zap.Duration("took", took))
+func (l *Logger) Named(name string) *Logger {
+	named := l.Logger.Named(name)
+	if l.levels == nil {
+		return l.derive(named)
+	}
+
+	enabler := l.levels.enabler(named.Name())
+	named = named.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newLevelFilterCore(unwrapLevelFilter(core), enabler)
+	}))
+	return l.derive(named)
+}
+
+// SetLevel changes the minimum level of the global logger at runtime.
+//
+// This allows flipping a running service to DEBUG and back without a restart.
+//
+// Example:
+//
+//	if err := logger.SetLevel(logger.LogLevelDebug); err != nil {
+//	    return err
+//	}
+//	defer logger.SetLevel(logger.LogLevelInfo)
+func SetLevel(level LogLevel) error {
+	log, err := TryGet()
+	if err != nil {
+		return err
+	}
+	return log.SetLevel(level)
+}
+
+// GetLevel returns the current minimum level of the global logger.
+//
+// This function panics if the logger has not been initialized.
+func GetLevel() LogLevel {
+	return Get().GetLevel()
+}
+
+// Named creates a named child of the global logger.
+//
+// See (*Logger).Named for how names and level overrides are resolved.
+//
+// Example:
+//
+//	log := logger.Named("http")
+//	log.Info("server listening", zap.String("addr", addr))
+func Named(name string) *Logger {
+	return Get().Named(name)
+}
@@ -0,0 +1,278 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestToZapLevel tests the conversion between configuration and zap levels.
+func TestToZapLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     config.LogLevel
+		want      zapcore.Level
+		wantError bool
+	}{
+		{name: "debug", level: config.LogLevelDebug, want: zapcore.DebugLevel},
+		{name: "info", level: config.LogLevelInfo, want: zapcore.InfoLevel},
+		{name: "warn", level: config.LogLevelWarn, want: zapcore.WarnLevel},
+		{name: "error", level: config.LogLevelError, want: zapcore.ErrorLevel},
+		{name: "invalid", level: config.LogLevel("TRACE"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toZapLevel(tt.level)
+			if tt.wantError {
+				if !errors.Is(err, ErrInvalidLogLevel) {
+					t.Errorf("expected ErrInvalidLogLevel but got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("toZapLevel() = %v, want %v", got, tt.want)
+			}
+			if back := fromZapLevel(got); back != tt.level {
+				t.Errorf("fromZapLevel() = %v, want %v", back, tt.level)
+			}
+		})
+	}
+}
+
+// TestLogger_SetLevel tests that the level can be changed at runtime.
+func TestLogger_SetLevel(t *testing.T) {
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	if log.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("debug should be disabled at INFO")
+	}
+
+	if err := log.SetLevel(LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := log.GetLevel(); got != LogLevelDebug {
+		t.Errorf("GetLevel() = %v, want %v", got, LogLevelDebug)
+	}
+	if !log.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("debug should be enabled after SetLevel(DEBUG)")
+	}
+
+	if err := log.SetLevel(LogLevel("VERBOSE")); !errors.Is(err, ErrInvalidLogLevel) {
+		t.Errorf("expected ErrInvalidLogLevel but got: %v", err)
+	}
+	if got := log.GetLevel(); got != LogLevelDebug {
+		t.Errorf("invalid level should not change the level, got %v", got)
+	}
+}
+
+// TestLogger_SetLevel_Derived tests that derived loggers share the level.
+func TestLogger_SetLevel_Derived(t *testing.T) {
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	extra := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "message"}),
+		zapcore.AddSync(os.Stdout),
+		zapcore.DebugLevel,
+	)
+	derived := map[string]*Logger{
+		"With":         log.With(zap.String("component", "auth")),
+		"WithCore":     log.WithCore(extra),
+		"WithOTELCore": log.WithOTELCore(extra),
+	}
+
+	for name, d := range derived {
+		if d.Core().Enabled(zapcore.DebugLevel) {
+			t.Errorf("%s: debug should be disabled at INFO", name)
+		}
+	}
+
+	if err := log.SetLevel(LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, d := range derived {
+		if !d.Core().Enabled(zapcore.DebugLevel) {
+			t.Errorf("%s: debug should be enabled after SetLevel(DEBUG)", name)
+		}
+	}
+
+	if err := derived["With"].SetLevel(LogLevelError); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := log.GetLevel(); got != LogLevelError {
+		t.Errorf("level set on derived logger should be shared, got %v", got)
+	}
+}
+
+// TestLogger_SetLevel_NotAdjustable tests loggers that were not built by this package.
+func TestLogger_SetLevel_NotAdjustable(t *testing.T) {
+	log := &Logger{Logger: zap.NewNop()}
+
+	if err := log.SetLevel(LogLevelDebug); !errors.Is(err, ErrLevelNotAdjustable) {
+		t.Errorf("expected ErrLevelNotAdjustable but got: %v", err)
+	}
+	if log.WithCore(zapcore.NewNopCore()) == nil {
+		t.Error("expected logger but got nil")
+	}
+}
+
+// TestSetLevel tests the package-level SetLevel and GetLevel functions.
+func TestSetLevel(t *testing.T) {
+	t.Run("changes global level", func(t *testing.T) {
+		resetGlobalLogger()
+		defer resetGlobalLogger()
+
+		cfg := config.LoggerConfig{
+			Level:       config.LogLevelWarn,
+			Environment: config.EnvDevelopment,
+			ServiceName: "test-service",
+		}
+		if err := InitGlobal(cfg); err != nil {
+			t.Fatalf("failed to initialize: %v", err)
+		}
+
+		if got := GetLevel(); got != LogLevelWarn {
+			t.Errorf("GetLevel() = %v, want %v", got, LogLevelWarn)
+		}
+		if err := SetLevel(LogLevelDebug); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := GetLevel(); got != LogLevelDebug {
+			t.Errorf("GetLevel() = %v, want %v", got, LogLevelDebug)
+		}
+	})
+
+	t.Run("returns error when not initialized", func(t *testing.T) {
+		resetGlobalLogger()
+
+		if err := SetLevel(LogLevelDebug); !errors.Is(err, ErrNotInitialized) {
+			t.Errorf("expected ErrNotInitialized but got: %v", err)
+		}
+	})
+}
+
+// TestLogger_Named tests hierarchical per-logger level overrides.
+func TestLogger_Named(t *testing.T) {
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+		Levels: map[string]config.LogLevel{
+			"db":   config.LogLevelDebug,
+			"http": config.LogLevelWarn,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		logger *Logger
+		want   LogLevel
+	}{
+		{name: "root", logger: log, want: LogLevelInfo},
+		{name: "db", logger: log.Named("db"), want: LogLevelDebug},
+		{name: "db.pool inherits db", logger: log.Named("db").Named("pool"), want: LogLevelDebug},
+		{name: "http", logger: log.Named("http"), want: LogLevelWarn},
+		{name: "unconfigured", logger: log.Named("cache"), want: LogLevelInfo},
+		{name: "name prefix is not an ancestor", logger: log.Named("dbx"), want: LogLevelInfo},
+		{name: "With keeps override", logger: log.Named("db").With(zap.String("k", "v")), want: LogLevelDebug},
+		{name: "WithCore keeps name", logger: log.Named("http").WithCore(zapcore.NewNopCore()), want: LogLevelWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.logger.GetLevel(); got != tt.want {
+				t.Errorf("GetLevel() = %v, want %v", got, tt.want)
+			}
+			want, _ := toZapLevel(tt.want)
+			if want > zapcore.DebugLevel && tt.logger.Core().Enabled(want-1) {
+				t.Errorf("level %v should be disabled", want-1)
+			}
+			if tt.name != "WithCore keeps name" && !tt.logger.Core().Enabled(want) {
+				t.Errorf("level %v should be enabled", want)
+			}
+		})
+	}
+
+	if got := log.Named("db").Named("pool").Name(); got != "db.pool" {
+		t.Errorf("Name() = %q, want %q", got, "db.pool")
+	}
+}
+
+// TestLogger_Named_SetLevel tests changing levels of named loggers at runtime.
+func TestLogger_Named_SetLevel(t *testing.T) {
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	db := log.Named("db")
+	pool := db.Named("pool")
+
+	if err := db.SetLevel(LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := pool.GetLevel(); got != LogLevelDebug {
+		t.Errorf("descendant level = %v, want %v", got, LogLevelDebug)
+	}
+	if got := log.GetLevel(); got != LogLevelInfo {
+		t.Errorf("root level = %v, want %v", got, LogLevelInfo)
+	}
+
+	if err := log.SetLevel(LogLevelError); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := db.GetLevel(); got != LogLevelDebug {
+		t.Errorf("override should survive root change, got %v", got)
+	}
+	if got := log.Named("cache").GetLevel(); got != LogLevelError {
+		t.Errorf("unconfigured logger level = %v, want %v", got, LogLevelError)
+	}
+}
+
+// TestNamed tests the package-level Named function.
+func TestNamed(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	cfg := config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvDevelopment,
+		ServiceName: "test-service",
+		Levels:      map[string]config.LogLevel{"db": config.LogLevelDebug},
+	}
+	if err := InitGlobal(cfg); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+
+	if got := Named("db").GetLevel(); got != LogLevelDebug {
+		t.Errorf("GetLevel() = %v, want %v", got, LogLevelDebug)
+	}
+}
@@ -1,11 +1,32 @@
 // Package logger provides a structured logging wrapper around Uber's Zap logger.
 //
 // This package offers a production-ready logging solution with:
-//   - Environment-based configuration (development/production)
+//   - Environment-based configuration with per-environment presets
+//   - Configuration files in YAML, JSON or TOML, overridable by environment
+//   - Public config package for application settings (struct tags, typed accessors)
+//   - Runtime-adjustable log level
+//   - Hot reload of the configuration from a watched file
 //   - Structured logging with strongly-typed fields
 //   - Global logger instance with thread-safe initialization
+//   - Standalone loggers built with New, independent of the global one
 //   - Convenient package-level functions
 //   - Support for contextual loggers with pre-attached fields
+//   - context.Context integration for loggers and fields, with automatic
+//     OpenTelemetry trace correlation
+//   - Named loggers with per-component level overrides
+//   - Multiple outputs (stdout, stderr, rotating files, network) with
+//     per-output level and encoding
+//   - Optional asynchronous writing with a bounded buffer and overflow policy
+//   - Per-level sampling of repeated entries, with dropped entries counted
+//   - Deduplication of identical entries into "message repeated N times" summaries
+//   - Token-bucket rate limiting per call site or named logger
+//   - Redaction of sensitive field values and messages, including nested fields
+//   - Keyed tokenization of sensitive identifiers with Token, re-identifiable
+//     offline by key holders
+//   - Built-in OTLP log export over HTTP or gRPC
+//   - log/slog handler writing into the same core
+//   - Redirection of the standard library log package
+//   - logr.Logger adapter for Kubernetes controller code
 //   - Integration-friendly design with OTEL support
 //
 // Basic usage:
@@ -29,13 +50,13 @@ package logger
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/gath-stack/gologger/internal/config"
+	"github.com/gath-stack/gologger/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -43,6 +64,22 @@ import (
 // Logger wraps zap.Logger to provide additional functionality.
 type Logger struct {
 	*zap.Logger
+
+	// levels is shared by every logger derived from the same root so that
+	// SetLevel on any of them takes effect everywhere.
+	levels *levelRegistry
+
+	// gen holds the generation the root logger writes to: its pipeline,
+	// outputs and configuration. It is shared by every derived logger so
+	// that they follow a rebuild, and nil for loggers not built by this
+	// package.
+	gen *atomic.Pointer[generation]
+
+	// skipped is Logger reporting the caller one frame further up, for the
+	// package-level and Ctx functions wrapping its methods. It is built on
+	// first use by callerSkipped.
+	skipped     *zap.Logger
+	skippedOnce sync.Once
 }
 
 var (
@@ -50,25 +87,23 @@ var (
 	mu           sync.RWMutex
 )
 
-// buildLogger constructs a zap.Logger based on the provided configuration.
-func buildLogger(cfg config.LoggerConfig) (*zap.Logger, error) {
-	// Parse log level
-	var level zapcore.Level
-	switch cfg.Level {
-	case config.LogLevelDebug:
-		level = zapcore.DebugLevel
-	case config.LogLevelInfo:
-		level = zapcore.InfoLevel
-	case config.LogLevelWarn:
-		level = zapcore.WarnLevel
-	case config.LogLevelError:
-		level = zapcore.ErrorLevel
-	default:
-		return nil, fmt.Errorf("%w: %s", ErrInvalidLogLevel, cfg.Level)
-	}
-
-	// Build encoder config
-	encoderConfig := zapcore.EncoderConfig{
+// callerSkipped returns the zap logger used by functions wrapping the
+// methods of l, so that entries report the caller of the wrapper.
+func (l *Logger) callerSkipped() *zap.Logger {
+	l.skippedOnce.Do(func() {
+		l.skipped = l.Logger.WithOptions(zap.AddCallerSkip(1))
+	})
+	return l.skipped
+}
+
+// derive returns a logger wrapping zapLogger that shares this logger's state.
+func (l *Logger) derive(zapLogger *zap.Logger) *Logger {
+	return &Logger{Logger: zapLogger, levels: l.levels, gen: l.gen}
+}
+
+// newEncoderConfig returns the encoder configuration shared by all outputs.
+func newEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
 		LevelKey:       "level",
 		NameKey:        "logger",
@@ -82,35 +117,57 @@ func buildLogger(cfg config.LoggerConfig) (*zap.Logger, error) {
 		EncodeDuration: zapcore.SecondsDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
+}
 
-	// Choose encoder based on environment
-	var encoder zapcore.Encoder
-	if cfg.Environment == config.EnvProduction {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	} else {
-		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+// buildLogger constructs a Logger based on the provided configuration.
+func buildLogger(cfg config.LoggerConfig) (*Logger, error) {
+	// Parse log level
+	zapLevel, err := toZapLevel(cfg.Level)
+	if err != nil {
+		return nil, err
 	}
-
-	// Create core
-	core := zapcore.NewCore(
-		encoder,
-		zapcore.AddSync(os.Stdout),
-		level,
-	)
+	levels, err := newLevelRegistry(zapLevel, cfg.Levels)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := buildGeneration(cfg, &counters{})
+	if err != nil {
+		return nil, err
+	}
+	current := &atomic.Pointer[generation]{}
+	current.Store(gen)
+	core := newLevelFilterCore(&swapCore{gen: current}, levels.enabler(""))
 
 	// Build logger with options
 	logger := zap.New(core,
 		zap.AddCaller(),
-		zap.AddCallerSkip(1),
-		zap.AddStacktrace(zapcore.ErrorLevel),
-		zap.Fields(zap.String("service", cfg.ServiceName)),
+		zap.AddStacktrace(stacktraceEnabler{gen: current}),
 	)
 
-	return logger, nil
+	return &Logger{
+		Logger: logger,
+		levels: levels,
+		gen:    current,
+	}, nil
 }
 
-// validateConfig validates the logger configuration.
+// stacktraceLevel returns the level from which entries carry a stack trace
+// in env: that of its preset, ERROR by default.
+func stacktraceLevel(env config.Environment) zapcore.Level {
+	preset, _ := env.Preset()
+	if preset.StacktraceLevel == "" {
+		return zapcore.ErrorLevel
+	}
+	level, err := toZapLevel(preset.StacktraceLevel)
+	if err != nil {
+		return zapcore.ErrorLevel
+	}
+	return level
+}
+
+// validateConfig validates the logger configuration: the level, environment
+// and service name are reported with their sentinel errors, then every
+// section is checked by cfg.Validate, as when the configuration is loaded.
 func validateConfig(cfg config.LoggerConfig) error {
 	if err := cfg.Level.Validate(); err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidLogLevel, err)
@@ -121,13 +178,19 @@ func validateConfig(cfg config.LoggerConfig) error {
 	if strings.TrimSpace(cfg.ServiceName) == "" {
 		return ErrMissingServiceName
 	}
-	return nil
+	for name, level := range cfg.Levels {
+		if err := level.Validate(); err != nil {
+			return fmt.Errorf("%w: logger '%s': %v", ErrInvalidLogLevel, name, err)
+		}
+	}
+	return cfg.Validate()
 }
 
 // InitGlobal initializes the global logger with the provided configuration.
 //
 // This function can only be called once. Subsequent calls will return
-// ErrAlreadyInitialized. The initialization is thread-safe.
+// ErrAlreadyInitialized. The initialization is thread-safe. Options such as
+// WithSlogDefault and WithStdLogRedirect are applied once the logger is built.
 //
 // Example:
 //
@@ -139,7 +202,7 @@ func validateConfig(cfg config.LoggerConfig) error {
 //	if err := logger.InitGlobal(cfg); err != nil {
 //	    log.Fatalf("failed to initialize logger: %v", err)
 //	}
-func InitGlobal(cfg config.LoggerConfig) error {
+func InitGlobal(cfg config.LoggerConfig, opts ...Option) error {
 	mu.Lock()
 	defer mu.Unlock()
 
@@ -148,21 +211,53 @@ func InitGlobal(cfg config.LoggerConfig) error {
 		return ErrAlreadyInitialized
 	}
 
-	// Validate config
-	if err := validateConfig(cfg); err != nil {
+	options := newInitOptions(opts)
+	if err := options.validate(); err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
 	}
 
-	// Build logger
-	zapLogger, err := buildLogger(cfg)
+	logger, err := New(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to build logger: %w", err)
+		return err
 	}
 
-	globalLogger = &Logger{Logger: zapLogger}
+	globalLogger = logger
+	options.apply(logger)
 	return nil
 }
 
+// New builds a standalone logger from the provided configuration.
+//
+// The configuration is validated and the logger is built exactly as in
+// InitGlobal, but the global logger is neither read nor modified, so any
+// number of independent loggers can coexist in one process. The caller owns
+// the returned logger and should Close it when done to release its outputs.
+//
+// Example:
+//
+//	log, err := logger.New(logger.LoggerConfig{
+//	    Level:       logger.LogLevelDebug,
+//	    Environment: logger.EnvDevelopment,
+//	    ServiceName: "billing-worker",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer log.Close()
+//
+//	log.Info("worker started")
+func New(cfg config.LoggerConfig) (*Logger, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	logger, err := buildLogger(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logger: %w", err)
+	}
+	return logger, nil
+}
+
 // Get returns the global logger instance.
 //
 // This function panics if the logger has not been initialized.
@@ -208,13 +303,14 @@ func TryGet() (*Logger, error) {
 // With returns a derived logger enriched with additional structured fields.
 //
 // The returned logger is a new instance and does not modify the original logger.
+// It shares the level of the original logger.
 //
 // Example:
 //
 //	log := logger.Get().With(zap.String("user_id", "abc123"))
 //	log.Info("User login succeeded")
 func (l *Logger) With(fields ...zap.Field) *Logger {
-	return &Logger{Logger: l.Logger.With(fields...)}
+	return l.derive(l.Logger.With(fields...))
 }
 
 // Sync flushes any buffered log entries to the underlying writer.
@@ -244,6 +340,32 @@ func (l *Logger) Sync() error {
 	return nil
 }
 
+// Close flushes buffered entries and releases the outputs, such as log files,
+// opened for this logger.
+//
+// Outputs are shared by every logger derived from the same root, so after
+// Close none of them should be used. Loggers without outputs to release only
+// flush.
+//
+// Example:
+//
+//	log, err := logger.TryGet()
+//	...
+//	defer log.Close()
+func (l *Logger) Close() error {
+	err := l.Sync()
+	gen := l.current()
+	if gen == nil {
+		return err
+	}
+	for _, c := range gen.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
 // isIgnorableSyncError returns true for sync errors that can be safely ignored.
 // Zap can fail on /dev/stderr in some operating systems.
 func isIgnorableSyncError(err error) bool {
@@ -254,29 +376,29 @@ func isIgnorableSyncError(err error) bool {
 
 // Debug logs a message at the DEBUG level using the global logger.
 func Debug(msg string, fields ...zap.Field) {
-	Get().Debug(msg, fields...)
+	Get().callerSkipped().Debug(msg, fields...)
 }
 
 // Info logs a message at the INFO level using the global logger.
 func Info(msg string, fields ...zap.Field) {
-	Get().Info(msg, fields...)
+	Get().callerSkipped().Info(msg, fields...)
 }
 
 // Warn logs a message at the WARN level using the global logger.
 func Warn(msg string, fields ...zap.Field) {
-	Get().Warn(msg, fields...)
+	Get().callerSkipped().Warn(msg, fields...)
 }
 
 // Error logs a message at the ERROR level using the global logger.
 func Error(msg string, fields ...zap.Field) {
-	Get().Error(msg, fields...)
+	Get().callerSkipped().Error(msg, fields...)
 }
 
 // Fatal logs a message at the FATAL level and terminates the application.
 //
 // Use this sparingly—prefer returning errors whenever possible.
 func Fatal(msg string, fields ...zap.Field) {
-	Get().Fatal(msg, fields...)
+	Get().callerSkipped().Fatal(msg, fields...)
 }
 
 // With creates a derived logger with pre-attached structured fields using the global logger.
@@ -310,7 +432,9 @@ func (l *Logger) UnderlyingLogger() *zap.Logger {
 // Use this when you need to replace or wrap the logger's core, such as
 // adding additional outputs (like OTLP) while maintaining existing configuration.
 //
-// The returned logger is a new instance with the new core.
+// The returned logger is a new instance with the new core. It keeps the name
+// of the original logger and honors the level shared with it, so SetLevel
+// still applies.
 //
 // Example:
 //
@@ -321,12 +445,22 @@ func (l *Logger) UnderlyingLogger() *zap.Logger {
 //	newLog := log.WithCore(teeCore)
 //	newLog.Info("This goes to both console and OTLP")
 func (l *Logger) WithCore(core zapcore.Core) *Logger {
+	name := l.Logger.Name()
+	if l.levels != nil {
+		core = newLevelFilterCore(core, l.levels.enabler(name))
+	}
+	var stacktrace zapcore.LevelEnabler = zapcore.ErrorLevel
+	if l.gen != nil {
+		stacktrace = stacktraceEnabler{gen: l.gen}
+	}
 	newLogger := zap.New(core,
 		zap.AddCaller(),
-		zap.AddCallerSkip(1),
-		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.AddStacktrace(stacktrace),
 	)
-	return &Logger{Logger: newLogger}
+	if name != "" {
+		newLogger = newLogger.Named(name)
+	}
+	return l.derive(newLogger)
 }
 
 // WithOTELCore creates a new logger that sends logs to both console and OTLP.
@@ -334,6 +468,8 @@ func (l *Logger) WithCore(core zapcore.Core) *Logger {
 // UNSTABLE API: This method is for advanced use cases and may change.
 // This is a convenience method for adding OTLP export to the logger.
 // The returned logger will write to both the original output and OTLP.
+// Entries sent to OTLP are redacted and tokenized as the logger's own (see
+// LoggerConfig.Redaction and LoggerConfig.Tokenization).
 //
 // Example:
 //
@@ -342,7 +478,12 @@ func (l *Logger) WithCore(core zapcore.Core) *Logger {
 //	newLog := log.WithOTELCore(otelCore)
 //	newLog.Info("This goes to both console and Loki")
 func (l *Logger) WithOTELCore(otelCore zapcore.Core) *Logger {
-	currentCore := l.Logger.Core()
+	currentCore := unwrapLevelFilter(l.Logger.Core())
+	if gen := l.current(); gen != nil {
+		if redact, err := buildRedactor(*gen.cfg); err == nil && redact != nil {
+			otelCore = newRedactCore(otelCore, redact)
+		}
+	}
 	teeCore := zapcore.NewTee(currentCore, otelCore)
 	return l.WithCore(teeCore)
 }
@@ -355,10 +496,11 @@ func (l *Logger) WithOTELCore(otelCore zapcore.Core) *Logger {
 //
 // Required environment variables:
 //   - LOG_LEVEL: DEBUG, INFO, WARN, ERROR
-//   - APP_ENV: development, production
+//   - APP_ENV: development, test, staging, production (see config.RegisterEnvironment)
 //   - APP_NAME: your service name
 //
-// Returns an error if any required variable is missing or invalid.
+// Use WithLoadOptions to read prefixed or renamed variables, or other .env
+// files. Returns an error if any required variable is missing or invalid.
 //
 // Example:
 //
@@ -366,12 +508,32 @@ func (l *Logger) WithOTELCore(otelCore zapcore.Core) *Logger {
 //	    log.Fatalf("failed to initialize logger: %v", err)
 //	}
 //	defer logger.Sync()
-func InitFromEnv() error {
-	cfg, err := config.Load()
+func InitFromEnv(opts ...Option) error {
+	cfg, err := newInitOptions(opts).load.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
-	return InitGlobal(cfg.Logger)
+	return InitGlobal(cfg.Logger, opts...)
+}
+
+// InitFromFile initializes the global logger from a YAML, JSON or TOML
+// configuration file.
+//
+// Environment variables override the values from the file. See
+// config.LoadFile for the file layout.
+//
+// Example:
+//
+//	if err := logger.InitFromFile("config/logging.yaml"); err != nil {
+//	    log.Fatalf("failed to initialize logger: %v", err)
+//	}
+//	defer logger.Sync()
+func InitFromFile(path string, opts ...Option) error {
+	cfg, err := newInitOptions(opts).load.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return InitGlobal(cfg.Logger, opts...)
 }
 
 // MustInitFromEnv initializes the global logger using environment variables.
@@ -381,7 +543,7 @@ func InitFromEnv() error {
 //
 // Required environment variables:
 //   - LOG_LEVEL: DEBUG, INFO, WARN, ERROR
-//   - APP_ENV: development, production
+//   - APP_ENV: development, test, staging, production (see config.RegisterEnvironment)
 //   - APP_NAME: your service name
 //
 // Example:
@@ -392,8 +554,8 @@ func InitFromEnv() error {
 //
 //	    logger.Info("application started")
 //	}
-func MustInitFromEnv() {
-	if err := InitFromEnv(); err != nil {
+func MustInitFromEnv(opts ...Option) {
+	if err := InitFromEnv(opts...); err != nil {
 		panic(fmt.Sprintf("failed to initialize logger from environment: %v", err))
 	}
 }
@@ -410,13 +572,13 @@ func MustInitFromEnv() {
 //
 //	logger.InitWithDefaults()
 //	defer logger.Sync()
-func InitWithDefaults() error {
+func InitWithDefaults(opts ...Option) error {
 	cfg := config.LoggerConfig{
 		Level:       config.LogLevelInfo,
 		Environment: config.EnvDevelopment,
 		ServiceName: "app",
 	}
-	return InitGlobal(cfg)
+	return InitGlobal(cfg, opts...)
 }
 
 // SyncWithTimeout flushes log entries with a timeout.
@@ -458,22 +620,86 @@ func (l *Logger) SyncWithTimeout(timeout time.Duration) error {
 	}
 }
 
-// ReplaceGlobal replaces the global logger with a new instance.
+// ReplaceGlobal replaces the global logger with a new instance and returns a
+// function that restores the previous one.
 //
 // This is useful when you need to enhance the logger after initialization,
-// such as adding OTLP export or other integrations.
+// such as adding OTLP export or other integrations, and in tests that need a
+// specific global logger for their duration.
 //
 // CAUTION: This function should be used sparingly and only during application
 // startup or configuration phases. Replacing the logger during normal operation
-// may cause unexpected behavior.
+// may cause unexpected behavior. Use Reconfigure to apply a new configuration.
 //
 // Example:
 //
 //	log := logger.Get()
 //	enhancedLog := log.WithOTELCore(otelCore)
 //	logger.ReplaceGlobal(enhancedLog)
-func ReplaceGlobal(newLogger *Logger) {
+//
+// In tests:
+//
+//	restore := logger.ReplaceGlobal(testLogger)
+//	defer restore()
+func ReplaceGlobal(newLogger *Logger) func() {
 	mu.Lock()
 	defer mu.Unlock()
+	previous := globalLogger
 	globalLogger = newLogger
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		globalLogger = previous
+	}
+}
+
+// Reconfigure builds a new global logger from cfg, swaps it in atomically and
+// returns the previous one.
+//
+// The configuration is validated and the logger built exactly as in
+// InitGlobal; on error the current global logger is left untouched. Unlike
+// InitGlobal, Reconfigure may be called any number of times, and also
+// initializes the global logger if it is not yet (the previous logger is then
+// nil).
+//
+// After the swap the previous logger is flushed; a flush failure is returned
+// together with the previous logger, the swap having taken place. The
+// previous logger's outputs stay open so that loggers derived from it keep
+// working: Close it once it is no longer in use. Options are applied to the
+// new logger as in InitGlobal; pass WithSlogDefault or WithStdLogRedirect
+// again to route slog and the log package to the new logger.
+//
+// Example:
+//
+//	previous, err := logger.Reconfigure(newCfg)
+//	if err != nil {
+//	    logger.Error("config rejected", zap.Error(err))
+//	    return
+//	}
+//	if previous != nil {
+//	    defer previous.Close()
+//	}
+func Reconfigure(cfg config.LoggerConfig, opts ...Option) (*Logger, error) {
+	options := newInitOptions(opts)
+	if err := options.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	logger, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	previous := globalLogger
+	globalLogger = logger
+	options.apply(logger)
+	mu.Unlock()
+
+	if previous != nil {
+		if err := previous.Sync(); err != nil {
+			return previous, err
+		}
+	}
+	return previous, nil
 }
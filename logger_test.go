@@ -3,12 +3,14 @@ package logger
 import (
 	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
 	"time"
 
-	"github.com/gath-stack/gologger/internal/config"
+	"github.com/gath-stack/gologger/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -95,6 +97,63 @@ func TestBuildLogger(t *testing.T) {
 	}
 }
 
+// TestBuildLogger_Preset tests that the environment preset configures sampling and stack traces.
+func TestBuildLogger_Preset(t *testing.T) {
+	err := config.RegisterEnvironment("preset-test", config.Preset{Sampling: true, StacktraceLevel: config.LogLevelWarn})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: "preset-test",
+		ServiceName: "test-service",
+		Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+	for i := 0; i < 300; i++ {
+		log.Info("repeated")
+	}
+	log.Warn("with stack")
+	_ = log.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if n := strings.Count(string(data), `"message":"repeated"`); n < 100 || n >= 300 {
+		t.Errorf("expected repeated entries to be sampled, got %d", n)
+	}
+	if n := strings.Count(string(data), `"stacktrace"`); n != 1 {
+		t.Errorf("expected a stack trace on the WARN entry only, got %d", n)
+	}
+
+	t.Run("explicitly disabled sampling", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		log, err := buildLogger(config.LoggerConfig{
+			Level:       config.LogLevelInfo,
+			Environment: "preset-test",
+			ServiceName: "test-service",
+			Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+			Sampling:    config.SamplingConfig{Disabled: true},
+		})
+		if err != nil {
+			t.Fatalf("failed to build logger: %v", err)
+		}
+		for i := 0; i < 300; i++ {
+			log.Info("repeated")
+		}
+		_ = log.Close()
+
+		if n := len(readLines(t, path)); n != 300 {
+			t.Errorf("expected all 300 entries when sampling is disabled, got %d", n)
+		}
+	})
+}
+
 // TestValidateConfig tests the validateConfig function.
 func TestValidateConfig(t *testing.T) {
 	tests := []struct {
@@ -124,7 +183,7 @@ func TestValidateConfig(t *testing.T) {
 			name: "invalid environment",
 			config: config.LoggerConfig{
 				Level:       config.LogLevelInfo,
-				Environment: config.Environment("staging"),
+				Environment: config.Environment("prod"),
 				ServiceName: "test-service",
 			},
 			wantError: ErrInvalidEnvironment,
@@ -147,6 +206,16 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantError: ErrMissingServiceName,
 		},
+		{
+			name: "invalid level override",
+			config: config.LoggerConfig{
+				Level:       config.LogLevelInfo,
+				Environment: config.EnvDevelopment,
+				ServiceName: "test-service",
+				Levels:      map[string]config.LogLevel{"db": "TRACE"},
+			},
+			wantError: ErrInvalidLogLevel,
+		},
 	}
 
 	for _, tt := range tests {
@@ -248,6 +317,139 @@ func TestInitGlobal(t *testing.T) {
 	}
 }
 
+// TestNew tests building independent loggers without touching the global logger.
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    config.LoggerConfig
+		wantError error
+	}{
+		{
+			name: "valid configuration",
+			config: config.LoggerConfig{
+				Level:       config.LogLevelInfo,
+				Environment: config.EnvProduction,
+				ServiceName: "test-service",
+			},
+		},
+		{
+			name: "invalid level",
+			config: config.LoggerConfig{
+				Level:       config.LogLevel("INVALID"),
+				Environment: config.EnvProduction,
+				ServiceName: "test-service",
+			},
+			wantError: ErrInvalidConfig,
+		},
+		{
+			name: "missing service name",
+			config: config.LoggerConfig{
+				Level:       config.LogLevelInfo,
+				Environment: config.EnvProduction,
+			},
+			wantError: ErrInvalidConfig,
+		},
+		{
+			name: "negative rate limit burst",
+			config: config.LoggerConfig{
+				Level:       config.LogLevelInfo,
+				Environment: config.EnvProduction,
+				ServiceName: "test-service",
+				RateLimit:   config.RateLimitConfig{Enabled: true, Rate: 10, Burst: -1},
+			},
+			wantError: ErrInvalidConfig,
+		},
+		{
+			name: "negative sampling initial",
+			config: config.LoggerConfig{
+				Level:       config.LogLevelInfo,
+				Environment: config.EnvProduction,
+				ServiceName: "test-service",
+				Sampling:    config.SamplingConfig{Enabled: true, Initial: -1},
+			},
+			wantError: ErrInvalidConfig,
+		},
+		{
+			name: "negative dedup window",
+			config: config.LoggerConfig{
+				Level:       config.LogLevelInfo,
+				Environment: config.EnvProduction,
+				ServiceName: "test-service",
+				Dedup:       config.DedupConfig{Enabled: true, Window: -time.Second},
+			},
+			wantError: ErrInvalidConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobalLogger()
+			defer resetGlobalLogger()
+
+			log, err := New(tt.config)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("expected error %v but got: %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer log.Close()
+
+			if _, err := TryGet(); !errors.Is(err, ErrNotInitialized) {
+				t.Errorf("expected global logger to stay uninitialized, got %v", err)
+			}
+		})
+	}
+}
+
+// TestNew_Independent tests that loggers built with New do not share state.
+func TestNew_Independent(t *testing.T) {
+	dir := t.TempDir()
+	newLogger := func(name string) *Logger {
+		log, err := New(config.LoggerConfig{
+			Level:       config.LogLevelInfo,
+			Environment: config.EnvProduction,
+			ServiceName: name,
+			Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: dir + "/" + name + ".log"}}},
+		})
+		if err != nil {
+			t.Fatalf("failed to build logger: %v", err)
+		}
+		return log
+	}
+
+	first, second := newLogger("first"), newLogger("second")
+	if err := first.SetLevel(LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.Debug("from first")
+	second.Debug("from second")
+	second.Info("from second")
+	_ = first.Close()
+	_ = second.Close()
+
+	for name, want := range map[string][]string{
+		"first":  {`"message":"from first"`, `"service":"first"`},
+		"second": {`"message":"from second"`, `"service":"second"`, `"level":"info"`},
+	} {
+		data, err := os.ReadFile(dir + "/" + name + ".log")
+		if err != nil {
+			t.Fatalf("failed to read log file: %v", err)
+		}
+		if lines := strings.Count(string(data), "\n"); lines != 1 {
+			t.Errorf("%s: expected 1 entry, got %d:\n%s", name, lines, data)
+		}
+		for _, s := range want {
+			if !strings.Contains(string(data), s) {
+				t.Errorf("%s: expected %s in:\n%s", name, s, data)
+			}
+		}
+	}
+}
+
 // TestGet tests the Get function.
 func TestGet(t *testing.T) {
 	t.Run("returns logger when initialized", func(t *testing.T) {
@@ -701,6 +903,48 @@ func TestInitFromEnv(t *testing.T) {
 	}
 }
 
+// TestInitFromEnv_ValidationError tests that every configuration problem is reported.
+func TestInitFromEnv_ValidationError(t *testing.T) {
+	resetGlobalLogger()
+	clearTestEnv()
+	defer clearTestEnv()
+
+	os.Setenv("APP_ENV", "production")
+	os.Setenv("LOG_ASYNC_BUFFER_SIZE", "many")
+
+	err := InitFromEnv()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError but got: %v", err)
+	}
+	if len(verr.Errors) != 3 {
+		t.Errorf("expected 3 problems, got %d: %v", len(verr.Errors), err)
+	}
+	if !errors.Is(err, ErrMissingRequiredEnvVar) || !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("expected missing and invalid sentinels, got: %v", err)
+	}
+}
+
+// TestInitFromEnv_WithLoadOptions tests reading prefixed variables.
+func TestInitFromEnv_WithLoadOptions(t *testing.T) {
+	resetGlobalLogger()
+	clearTestEnv()
+	defer resetGlobalLogger()
+
+	os.Setenv("LOG_LEVEL", "INVALID") // read by another tool
+	defer clearTestEnv()
+	t.Setenv("MYSVC_LOG_LEVEL", "WARN")
+	t.Setenv("MYSVC_APP_ENV", "production")
+	t.Setenv("MYSVC_APP_NAME", "test-service")
+
+	if err := InitFromEnv(WithLoadOptions(LoadOptions{Prefix: "MYSVC_"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetLevel() != LogLevelWarn {
+		t.Errorf("expected level from MYSVC_LOG_LEVEL, got %s", GetLevel())
+	}
+}
+
 // TestMustInitFromEnv tests the MustInitFromEnv function.
 func TestMustInitFromEnv(t *testing.T) {
 	t.Run("panics on error", func(t *testing.T) {
@@ -739,6 +983,52 @@ func TestMustInitFromEnv(t *testing.T) {
 	})
 }
 
+// TestInitFromFile tests the InitFromFile function.
+func TestInitFromFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantError error
+	}{
+		{
+			name:    "successful initialization",
+			content: "logger:\n  level: WARN\n  environment: development\n  service_name: file-service\n",
+		},
+		{
+			name:      "invalid log level",
+			content:   "logger:\n  level: LOUD\n  environment: development\n  service_name: file-service\n",
+			wantError: config.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobalLogger()
+			clearTestEnv()
+			defer resetGlobalLogger()
+
+			path := filepath.Join(t.TempDir(), "logging.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write config file: %v", err)
+			}
+
+			err := InitFromFile(path)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("expected %v but got: %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if GetLevel() != LogLevelWarn {
+				t.Errorf("expected level from file, got %s", GetLevel())
+			}
+		})
+	}
+}
+
 // TestInitWithDefaults tests the InitWithDefaults function.
 func TestInitWithDefaults(t *testing.T) {
 	resetGlobalLogger()
@@ -812,6 +1102,92 @@ func TestLogger_SyncWithTimeout(t *testing.T) {
 	}
 }
 
+// TestReplaceGlobal tests replacing the global logger and restoring it.
+func TestReplaceGlobal(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	original, _ := newObservedLogger()
+	replacement, _ := newObservedLogger()
+
+	restoreOriginal := ReplaceGlobal(original)
+	restore := ReplaceGlobal(replacement)
+	if Get() != replacement {
+		t.Error("expected the replacement to be the global logger")
+	}
+
+	restore()
+	if Get() != original {
+		t.Error("expected restore to reinstate the previous logger")
+	}
+	restoreOriginal()
+	if _, err := TryGet(); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("expected logger to be uninitialized after restore, got %v", err)
+	}
+}
+
+// TestReconfigure tests swapping the global logger for a new configuration.
+func TestReconfigure(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	dir := t.TempDir()
+	fileConfig := func(level config.LogLevel, name string) config.LoggerConfig {
+		return config.LoggerConfig{
+			Level:       level,
+			Environment: config.EnvProduction,
+			ServiceName: "test-service",
+			Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: dir + "/" + name}}},
+		}
+	}
+
+	previous, err := Reconfigure(fileConfig(config.LogLevelInfo, "first.log"))
+	if err != nil || previous != nil {
+		t.Fatalf("expected initialization without previous logger, got %v, %v", previous, err)
+	}
+	first := Get()
+	Debug("dropped at INFO")
+
+	invalid := fileConfig(config.LogLevel("INVALID"), "invalid.log")
+	if _, err := Reconfigure(invalid); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+	if Get() != first {
+		t.Error("expected a rejected configuration to keep the current logger")
+	}
+
+	previous, err = Reconfigure(fileConfig(config.LogLevelDebug, "second.log"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if previous != first {
+		t.Error("expected the previous logger to be returned")
+	}
+	Debug("written at DEBUG")
+	_ = previous.Close()
+	_ = Get().Close()
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "first.log", want: ""},
+		{file: "second.log", want: "written at DEBUG"},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(dir + "/" + tt.file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", tt.file, err)
+		}
+		if tt.want == "" && len(data) != 0 {
+			t.Errorf("%s: expected no entries, got %s", tt.file, data)
+		}
+		if tt.want != "" && !strings.Contains(string(data), tt.want) {
+			t.Errorf("%s: expected %q in %s", tt.file, tt.want, data)
+		}
+	}
+}
+
 // TestConcurrentInitialization tests that concurrent initialization is thread-safe.
 func TestConcurrentInitialization(t *testing.T) {
 	resetGlobalLogger()
@@ -903,4 +1279,40 @@ func clearTestEnv() {
 	os.Unsetenv("LOG_LEVEL")
 	os.Unsetenv("APP_ENV")
 	os.Unsetenv("APP_NAME")
+	os.Unsetenv("LOG_LEVELS")
+	os.Unsetenv("LOG_FILE")
+	os.Unsetenv("LOG_OUTPUTS")
+	os.Unsetenv("LOG_ASYNC")
+	os.Unsetenv("LOG_ASYNC_BUFFER_SIZE")
+	os.Unsetenv("LOG_ASYNC_FLUSH_INTERVAL")
+	os.Unsetenv("LOG_ASYNC_OVERFLOW")
+	os.Unsetenv("LOG_ASYNC_DROP_BELOW")
+	os.Unsetenv("LOG_SAMPLING")
+	os.Unsetenv("LOG_SAMPLING_TICK")
+	os.Unsetenv("LOG_SAMPLING_INITIAL")
+	os.Unsetenv("LOG_SAMPLING_THEREAFTER")
+	os.Unsetenv("LOG_SAMPLING_LEVELS")
+	os.Unsetenv("LOG_DEDUP")
+	os.Unsetenv("LOG_DEDUP_WINDOW")
+	os.Unsetenv("LOG_RATE_LIMIT")
+	os.Unsetenv("LOG_RATE_LIMIT_BY")
+	os.Unsetenv("LOG_RATE_LIMIT_RATE")
+	os.Unsetenv("LOG_RATE_LIMIT_BURST")
+	os.Unsetenv("LOG_RATE_LIMIT_LEVELS")
+	os.Unsetenv("LOG_RATE_LIMIT_EXEMPT_ERRORS")
+	os.Unsetenv("LOG_REDACT")
+	os.Unsetenv("LOG_REDACT_KEYS")
+	os.Unsetenv("LOG_REDACT_KEY_PATTERNS")
+	os.Unsetenv("LOG_REDACT_VALUES")
+	os.Unsetenv("LOG_REDACT_ACTION")
+	os.Unsetenv("LOG_TOKEN_KEY")
+	os.Unsetenv("LOG_TOKEN_KEY_FILE")
+	os.Unsetenv("LOG_TOKEN_KEY_ID")
+	os.Unsetenv("LOG_TRACE_ID_KEY")
+	os.Unsetenv("LOG_SPAN_ID_KEY")
+	os.Unsetenv("LOG_TRACE_FLAGS_KEY")
+	os.Unsetenv("OTEL_LOGS_EXPORTER")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_LOGS_PROTOCOL")
+	os.Unsetenv("LOG_OTLP_LEVEL")
 }
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logrBadKey is the key used for values without a string key, as in log/slog.
+const logrBadKey = "!BADKEY"
+
+// logrSink is a logr.LogSink writing into a Logger.
+type logrSink struct {
+	log *Logger
+
+	// zap is log.Logger with the extra caller skip requested by logr.
+	zap   *zap.Logger
+	depth int
+}
+
+var (
+	_ logr.LogSink          = (*logrSink)(nil)
+	_ logr.CallDepthLogSink = (*logrSink)(nil)
+)
+
+// Logr returns a logr.Logger writing into this logger, for code such as
+// Kubernetes controllers that expects the logr API.
+//
+// V-levels map onto DEBUG and INFO: V(0) logs at INFO and V(1) and above log
+// at DEBUG, so verbose logs follow the logger's level, including runtime
+// changes made with SetLevel. Error logs at ERROR with the error in the
+// "error" field. WithName maps to Named, so per-logger level overrides apply,
+// and WithValues adds fields.
+//
+// Example:
+//
+//	ctrl.SetLogger(logger.Get().Logr())
+//
+//	log := logger.Get().Logr().WithName("reconciler")
+//	log.V(1).Info("reconciling", "namespace", ns, "name", name)
+func (l *Logger) Logr() logr.Logger {
+	return logr.New(newLogrSink(l, 0))
+}
+
+// Logr returns a logr.Logger writing into the global logger.
+//
+// See (*Logger).Logr for how calls are mapped.
+//
+// This function panics if the logger has not been initialized.
+func Logr() logr.Logger {
+	return Get().Logr()
+}
+
+// newLogrSink creates a sink for l skipping depth additional stack frames,
+// besides the frame of the sink itself.
+func newLogrSink(l *Logger, depth int) *logrSink {
+	return &logrSink{
+		log:   l,
+		zap:   l.Logger.WithOptions(zap.AddCallerSkip(1 + depth)),
+		depth: depth,
+	}
+}
+
+// Init receives the call depth of the logr.Logger wrapping the sink.
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	*s = *newLogrSink(s.log, s.depth+info.CallDepth)
+}
+
+// Enabled reports whether messages at the given V-level are logged.
+func (s *logrSink) Enabled(level int) bool {
+	return s.log.Logger.Core().Enabled(zapLevelFromLogr(level))
+}
+
+// Info logs a non-error message at the given V-level.
+func (s *logrSink) Info(level int, msg string, keysAndValues ...any) {
+	if ce := s.zap.Check(zapLevelFromLogr(level), msg); ce != nil {
+		ce.Write(logrFields(keysAndValues)...)
+	}
+}
+
+// Error logs an error at ERROR.
+func (s *logrSink) Error(err error, msg string, keysAndValues ...any) {
+	if ce := s.zap.Check(zapcore.ErrorLevel, msg); ce != nil {
+		ce.Write(append([]zap.Field{zap.Error(err)}, logrFields(keysAndValues)...)...)
+	}
+}
+
+// WithValues returns a sink whose messages include keysAndValues.
+func (s *logrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return newLogrSink(s.log.With(logrFields(keysAndValues)...), s.depth)
+}
+
+// WithName returns a sink for the named child logger.
+func (s *logrSink) WithName(name string) logr.LogSink {
+	return newLogrSink(s.log.Named(name), s.depth)
+}
+
+// WithCallDepth returns a sink skipping depth additional stack frames.
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	return newLogrSink(s.log, s.depth+depth)
+}
+
+// zapLevelFromLogr maps a logr V-level to a zap level: V(0) is INFO and
+// anything more verbose is DEBUG.
+func zapLevelFromLogr(level int) zapcore.Level {
+	if level > 0 {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+// logrFields converts logr key/value pairs to zap fields. A value without a
+// string key is logged under "!BADKEY".
+func logrFields(keysAndValues []any) []zap.Field {
+	fields := make([]zap.Field, 0, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); {
+		key, ok := keysAndValues[i].(string)
+		if !ok || i+1 == len(keysAndValues) {
+			fields = append(fields, zap.Any(logrBadKey, keysAndValues[i]))
+			i++
+			continue
+		}
+		fields = append(fields, logrField(key, keysAndValues[i+1]))
+		i += 2
+	}
+	return fields
+}
+
+// logrField returns the field for a key/value pair, honoring logr.Marshaler
+// as logr sinks are expected to.
+func logrField(key string, value any) zap.Field {
+	if m, ok := value.(logr.Marshaler); ok {
+		return zap.Any(key, m.MarshalLog())
+	}
+	return zap.Any(key, value)
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// logrUser implements logr.Marshaler.
+type logrUser struct{ name, password string }
+
+func (u logrUser) MarshalLog() any {
+	return map[string]string{"name": u.name}
+}
+
+// TestLogger_Logr tests the mapping of logr calls onto the logger.
+func TestLogger_Logr(t *testing.T) {
+	log, logs := newObservedLogger()
+	lr := log.Logr().WithName("controller").WithValues("namespace", "default")
+
+	lr.Info("reconciling", "name", "web", "user", logrUser{name: "bob", password: "secret"})
+	lr.V(2).Info("verbose", 42)
+	lr.Error(errors.New("conflict"), "update failed", "attempt", 3)
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	info := entries[0]
+	if info.Level != zapcore.InfoLevel || info.LoggerName != "controller" {
+		t.Errorf("unexpected entry: %v %q", info.Level, info.LoggerName)
+	}
+	fields := info.ContextMap()
+	if fields["namespace"] != "default" || fields["name"] != "web" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if user, ok := fields["user"].(map[string]string); !ok || user["password"] != "" || user["name"] != "bob" {
+		t.Errorf("expected logr.Marshaler to be honored, got %v", fields["user"])
+	}
+	if !strings.HasSuffix(info.Caller.File, "logr_test.go") {
+		t.Errorf("expected caller in logr_test.go, got %s", info.Caller.File)
+	}
+
+	verbose := entries[1]
+	if verbose.Level != zapcore.DebugLevel || verbose.ContextMap()[logrBadKey] != int64(42) {
+		t.Errorf("unexpected verbose entry: %v %v", verbose.Level, verbose.ContextMap())
+	}
+
+	failed := entries[2]
+	if failed.Level != zapcore.ErrorLevel || failed.ContextMap()["error"] != "conflict" || failed.ContextMap()["attempt"] != int64(3) {
+		t.Errorf("unexpected error entry: %v %v", failed.Level, failed.ContextMap())
+	}
+}
+
+// TestLogger_LogrLevel tests that V-levels follow the dynamic level.
+func TestLogger_LogrLevel(t *testing.T) {
+	log := newTestLevelLogger(t)
+	lr := log.Logr().WithName("db")
+
+	if lr.V(1).Enabled() {
+		t.Error("expected V(1) to be disabled at INFO")
+	}
+	if !lr.V(0).Enabled() {
+		t.Error("expected V(0) to be enabled at INFO")
+	}
+	if err := log.Named("db").SetLevel(LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !lr.V(3).Enabled() {
+		t.Error("expected V(3) to be enabled after SetLevel")
+	}
+	if log.Logr().V(1).Enabled() {
+		t.Error("expected the override to apply to db only")
+	}
+}
+
+// TestLogrFields tests the conversion of key/value pairs.
+func TestLogrFields(t *testing.T) {
+	tests := []struct {
+		name string
+		kvs  []any
+		want []string
+	}{
+		{name: "pairs", kvs: []any{"a", 1, "b", "two"}, want: []string{"a", "b"}},
+		{name: "non-string key", kvs: []any{1, "a", 2}, want: []string{logrBadKey, "a"}},
+		{name: "missing value", kvs: []any{"a", 1, "b"}, want: []string{"a", logrBadKey}},
+		{name: "empty", kvs: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := logrFields(tt.kvs)
+			if len(fields) != len(tt.want) {
+				t.Fatalf("expected %d fields, got %d", len(tt.want), len(fields))
+			}
+			for i, f := range fields {
+				if f.Key != tt.want[i] {
+					t.Errorf("field %d key = %q, want %q", i, f.Key, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/gath-stack/gologger/config"
+)
+
+// Option customizes the initialization of the global logger.
+type Option func(*initOptions)
+
+// initOptions holds the settings applied by Options.
+type initOptions struct {
+	slogDefault bool
+
+	stdLog      bool
+	stdLogLevel LogLevel
+
+	load config.LoadOptions
+}
+
+// newInitOptions applies opts to the default settings.
+func newInitOptions(opts []Option) initOptions {
+	var o initOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// validate checks the settings before the logger is built, so that an invalid
+// option leaves the global logger uninitialized.
+func (o initOptions) validate() error {
+	if o.stdLog {
+		if _, err := toZapLevel(o.stdLogLevel); err != nil {
+			return fmt.Errorf("standard log redirect: %w", err)
+		}
+	}
+	return nil
+}
+
+// apply performs the side effects requested by the options for the newly
+// initialized global logger. The options must have been validated.
+func (o initOptions) apply(l *Logger) {
+	if o.slogDefault {
+		slog.SetDefault(l.Slog())
+	}
+	if o.stdLog {
+		level, _ := toZapLevel(o.stdLogLevel)
+		l.redirectStdLog(level)
+	}
+}
+
+// WithSlogDefault makes the global logger the default slog logger, so that
+// slog.Info and friends, and libraries logging through slog.Default, write
+// through it.
+//
+// Example:
+//
+//	logger.MustInitFromEnv(logger.WithSlogDefault())
+//	slog.Info("routed through gologger")
+func WithSlogDefault() Option {
+	return func(o *initOptions) {
+		o.slogDefault = true
+	}
+}
+
+// WithStdLogRedirect redirects the standard library log package into the
+// global logger at the given level for the lifetime of the process.
+//
+// See (*Logger).RedirectStdLog for how entries are written. Use
+// RedirectStdLog instead when the redirect must be undone.
+//
+// Example:
+//
+//	logger.MustInitFromEnv(logger.WithStdLogRedirect(logger.LogLevelWarn))
+//	log.Printf("from a dependency") // logged as WARN with source="stdlib"
+func WithStdLogRedirect(level LogLevel) Option {
+	return func(o *initOptions) {
+		o.stdLog = true
+		o.stdLogLevel = level
+	}
+}
+
+// WithLoadOptions selects the environment variables and .env files read by
+// InitFromEnv, MustInitFromEnv, InitFromFile and the reloads of
+// WatchConfigFile, e.g. to read MYSVC_LOG_LEVEL instead of LOG_LEVEL. It has
+// no effect on InitGlobal, New and Reconfigure, which take a configuration.
+//
+// Example:
+//
+//	logger.MustInitFromEnv(logger.WithLoadOptions(logger.LoadOptions{Prefix: "MYSVC_"}))
+func WithLoadOptions(opts LoadOptions) Option {
+	return func(o *initOptions) {
+		o.load = opts
+	}
+}
@@ -0,0 +1,431 @@
+package logger
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// otlpScopeName is the instrumentation scope of exported records.
+	otlpScopeName = "github.com/gath-stack/gologger"
+
+	// otlpFlushTimeout bounds how long Sync waits for pending records.
+	otlpFlushTimeout = 5 * time.Second
+
+	// otlpShutdownTimeout bounds how long Close waits for the exporter.
+	otlpShutdownTimeout = 10 * time.Second
+
+	// Failed exports are retried with exponential backoff, starting at
+	// otlpRetryInitial and capped at otlpRetryMax, for up to otlpRetryElapsed.
+	otlpRetryInitial = 5 * time.Second
+	otlpRetryMax     = 30 * time.Second
+	otlpRetryElapsed = time.Minute
+)
+
+// newOTLPCore creates a core exporting entries as OpenTelemetry log records
+// through a batching LoggerProvider. The returned closer shuts the provider
+// down, exporting pending records.
+func newOTLPCore(cfg config.LoggerConfig) (zapcore.Core, io.Closer, error) {
+	minLevel := zapcore.DebugLevel
+	if cfg.OTLP.Level != "" {
+		level, err := toZapLevel(cfg.OTLP.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		minLevel = level
+	}
+
+	ctx := context.Background()
+	exporter, err := newOTLPExporter(ctx, cfg.OTLP)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attribute.String("service.name", cfg.ServiceName)),
+	)
+	if err != nil {
+		_ = exporter.Shutdown(ctx)
+		return nil, nil, fmt.Errorf("failed to create OTLP resource: %w", err)
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	core := &otlpCore{
+		LevelEnabler: minLevel,
+		logger:       provider.Logger(otlpScopeName),
+		provider:     provider,
+		trace:        cfg.Trace.WithDefaults(),
+	}
+	return core, otlpCloser{provider}, nil
+}
+
+// newOTLPExporter creates the exporter for the configured protocol.
+func newOTLPExporter(ctx context.Context, cfg config.OTLPConfig) (sdklog.Exporter, error) {
+	switch cfg.Protocol {
+	case "", config.OTLPProtocolHTTP:
+		opts := []otlploghttp.Option{otlploghttp.WithRetry(otlploghttp.RetryConfig{
+			Enabled:         true,
+			InitialInterval: otlpRetryInitial,
+			MaxInterval:     otlpRetryMax,
+			MaxElapsedTime:  otlpRetryElapsed,
+		})}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploghttp.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlploghttp.New(ctx, opts...)
+	case config.OTLPProtocolGRPC:
+		opts := []otlploggrpc.Option{otlploggrpc.WithRetry(otlploggrpc.RetryConfig{
+			Enabled:         true,
+			InitialInterval: otlpRetryInitial,
+			MaxInterval:     otlpRetryMax,
+			MaxElapsedTime:  otlpRetryElapsed,
+		})}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploggrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlploggrpc.New(ctx, opts...)
+	default:
+		return nil, cfg.Protocol.Validate()
+	}
+}
+
+// otlpCloser shuts down a LoggerProvider.
+type otlpCloser struct {
+	provider *sdklog.LoggerProvider
+}
+
+// Close exports pending records and stops the provider.
+func (c otlpCloser) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), otlpShutdownTimeout)
+	defer cancel()
+	return c.provider.Shutdown(ctx)
+}
+
+// otlpCore is a zapcore.Core emitting entries as OpenTelemetry log records.
+//
+// The message becomes the record body, the level its severity, and fields
+// its attributes. The logger name, caller and stack trace are added as the
+// "logger", "caller" and "stacktrace" attributes, matching the JSON output.
+// The trace fields added by the Ctx functions also set the trace context of
+// the record.
+type otlpCore struct {
+	zapcore.LevelEnabler
+	logger   otellog.Logger
+	provider *sdklog.LoggerProvider
+	fields   *otlpEncoder
+	trace    config.TraceConfig
+}
+
+// Level returns the minimum enabled level of the core.
+func (c *otlpCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.LevelEnabler)
+}
+
+// With adds structured context to the core.
+func (c *otlpCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = c.fields.clone()
+	for _, f := range fields {
+		f.AddTo(clone.fields)
+	}
+	return &clone
+}
+
+// Check adds the core to ce if the entry's level is enabled.
+func (c *otlpCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write emits the entry as a log record.
+func (c *otlpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := c.fields.clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	var record otellog.Record
+	record.SetTimestamp(ent.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(otlpSeverity(ent.Level))
+	record.SetSeverityText(ent.Level.CapitalString())
+	record.SetBody(otellog.StringValue(ent.Message))
+	if ent.LoggerName != "" {
+		record.AddAttributes(otellog.String("logger", ent.LoggerName))
+	}
+	if ent.Caller.Defined {
+		record.AddAttributes(otellog.String("caller", ent.Caller.TrimmedPath()))
+	}
+	if ent.Stack != "" {
+		record.AddAttributes(otellog.String("stacktrace", ent.Stack))
+	}
+	record.AddAttributes(enc.attributes()...)
+
+	ctx := context.Background()
+	if sc := c.spanContext(enc); sc.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, sc)
+	}
+	c.logger.Emit(ctx, record)
+	return nil
+}
+
+// spanContext returns the span context named by the top-level trace fields
+// of enc, as added by the Ctx functions. It is invalid if they are missing.
+func (c *otlpCore) spanContext(enc *otlpEncoder) trace.SpanContext {
+	var cfg trace.SpanContextConfig
+	for _, kv := range enc.attrs {
+		if kv.Value.Kind() != otellog.KindString {
+			continue
+		}
+		value := kv.Value.AsString()
+		switch kv.Key {
+		case c.trace.TraceIDKey:
+			cfg.TraceID, _ = trace.TraceIDFromHex(value)
+		case c.trace.SpanIDKey:
+			cfg.SpanID, _ = trace.SpanIDFromHex(value)
+		case c.trace.TraceFlagsKey:
+			if b, err := hex.DecodeString(value); err == nil && len(b) == 1 {
+				cfg.TraceFlags = trace.TraceFlags(b[0])
+			}
+		}
+	}
+	return trace.NewSpanContext(cfg)
+}
+
+// Sync exports pending records.
+func (c *otlpCore) Sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), otlpFlushTimeout)
+	defer cancel()
+	return c.provider.ForceFlush(ctx)
+}
+
+// otlpSeverity maps a zap level to an OpenTelemetry severity number.
+func otlpSeverity(level zapcore.Level) otellog.Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return otellog.SeverityDebug
+	case zapcore.InfoLevel:
+		return otellog.SeverityInfo
+	case zapcore.WarnLevel:
+		return otellog.SeverityWarn
+	case zapcore.ErrorLevel:
+		return otellog.SeverityError
+	case zapcore.DPanicLevel:
+		return otellog.SeverityFatal1
+	case zapcore.PanicLevel:
+		return otellog.SeverityFatal2
+	case zapcore.FatalLevel:
+		return otellog.SeverityFatal3
+	default:
+		return otellog.SeverityUndefined
+	}
+}
+
+// otlpNamespace is a namespace opened by zap.Namespace and the attributes
+// added to it so far.
+type otlpNamespace struct {
+	key   string
+	attrs []otellog.KeyValue
+}
+
+// otlpEncoder is a zapcore.ObjectEncoder collecting fields as log record
+// attributes. Namespaces become nested maps.
+type otlpEncoder struct {
+	attrs      []otellog.KeyValue
+	namespaces []otlpNamespace
+}
+
+// clone returns an independent copy of e. A nil encoder clones to an empty one.
+func (e *otlpEncoder) clone() *otlpEncoder {
+	clone := &otlpEncoder{}
+	if e == nil {
+		return clone
+	}
+	clone.attrs = append([]otellog.KeyValue(nil), e.attrs...)
+	for _, ns := range e.namespaces {
+		clone.namespaces = append(clone.namespaces, otlpNamespace{
+			key:   ns.key,
+			attrs: append([]otellog.KeyValue(nil), ns.attrs...),
+		})
+	}
+	return clone
+}
+
+// attributes returns the collected attributes with open namespaces closed.
+func (e *otlpEncoder) attributes() []otellog.KeyValue {
+	attrs := append([]otellog.KeyValue(nil), e.attrs...)
+	var nested []otellog.KeyValue
+	for i := len(e.namespaces) - 1; i >= 0; i-- {
+		ns := e.namespaces[i]
+		nested = append(append([]otellog.KeyValue(nil), ns.attrs...), nested...)
+		nested = []otellog.KeyValue{otellog.Map(ns.key, nested...)}
+	}
+	return append(attrs, nested...)
+}
+
+func (e *otlpEncoder) add(kv otellog.KeyValue) {
+	if n := len(e.namespaces); n > 0 {
+		e.namespaces[n-1].attrs = append(e.namespaces[n-1].attrs, kv)
+		return
+	}
+	e.attrs = append(e.attrs, kv)
+}
+
+func (e *otlpEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	values := &otlpArrayEncoder{}
+	err := arr.MarshalLogArray(values)
+	e.add(otellog.Slice(key, values.values...))
+	return err
+}
+
+func (e *otlpEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	nested := &otlpEncoder{}
+	err := obj.MarshalLogObject(nested)
+	e.add(otellog.Map(key, nested.attributes()...))
+	return err
+}
+
+func (e *otlpEncoder) AddBinary(key string, value []byte) { e.add(otellog.Bytes(key, value)) }
+func (e *otlpEncoder) AddByteString(key string, value []byte) {
+	e.add(otellog.String(key, string(value)))
+}
+func (e *otlpEncoder) AddBool(key string, value bool) { e.add(otellog.Bool(key, value)) }
+func (e *otlpEncoder) AddComplex128(key string, value complex128) {
+	e.add(otellog.String(key, fmt.Sprint(value)))
+}
+func (e *otlpEncoder) AddComplex64(key string, value complex64) {
+	e.add(otellog.String(key, fmt.Sprint(value)))
+}
+func (e *otlpEncoder) AddDuration(key string, value time.Duration) {
+	e.add(otellog.Float64(key, value.Seconds()))
+}
+func (e *otlpEncoder) AddFloat64(key string, value float64) { e.add(otellog.Float64(key, value)) }
+func (e *otlpEncoder) AddFloat32(key string, value float32) {
+	e.add(otellog.Float64(key, float64(value)))
+}
+func (e *otlpEncoder) AddInt(key string, value int)     { e.add(otellog.Int(key, value)) }
+func (e *otlpEncoder) AddInt64(key string, value int64) { e.add(otellog.Int64(key, value)) }
+func (e *otlpEncoder) AddInt32(key string, value int32) { e.add(otellog.Int64(key, int64(value))) }
+func (e *otlpEncoder) AddInt16(key string, value int16) { e.add(otellog.Int64(key, int64(value))) }
+func (e *otlpEncoder) AddInt8(key string, value int8)   { e.add(otellog.Int64(key, int64(value))) }
+func (e *otlpEncoder) AddString(key, value string)      { e.add(otellog.String(key, value)) }
+func (e *otlpEncoder) AddTime(key string, value time.Time) {
+	e.add(otellog.String(key, value.Format(time.RFC3339Nano)))
+}
+func (e *otlpEncoder) AddUint(key string, value uint)       { e.add(otlpUint(key, uint64(value))) }
+func (e *otlpEncoder) AddUint64(key string, value uint64)   { e.add(otlpUint(key, value)) }
+func (e *otlpEncoder) AddUint32(key string, value uint32)   { e.add(otlpUint(key, uint64(value))) }
+func (e *otlpEncoder) AddUint16(key string, value uint16)   { e.add(otlpUint(key, uint64(value))) }
+func (e *otlpEncoder) AddUint8(key string, value uint8)     { e.add(otlpUint(key, uint64(value))) }
+func (e *otlpEncoder) AddUintptr(key string, value uintptr) { e.add(otlpUint(key, uint64(value))) }
+
+func (e *otlpEncoder) AddReflected(key string, value any) error {
+	e.add(otellog.KeyValue{Key: key, Value: otlpReflectedValue(value)})
+	return nil
+}
+
+func (e *otlpEncoder) OpenNamespace(key string) {
+	e.namespaces = append(e.namespaces, otlpNamespace{key: key})
+}
+
+// otlpUint converts an unsigned integer, falling back to a string for values
+// that do not fit in an int64.
+func otlpUint(key string, value uint64) otellog.KeyValue {
+	if value > math.MaxInt64 {
+		return otellog.String(key, fmt.Sprint(value))
+	}
+	return otellog.Int64(key, int64(value))
+}
+
+// otlpReflectedValue converts an arbitrary value via its JSON representation.
+func otlpReflectedValue(value any) otellog.Value {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return otellog.StringValue(fmt.Sprintf("%+v", value))
+	}
+	return otellog.StringValue(string(data))
+}
+
+// otlpArrayEncoder is a zapcore.ArrayEncoder collecting log values.
+type otlpArrayEncoder struct {
+	values []otellog.Value
+}
+
+func (a *otlpArrayEncoder) append(v otellog.Value) { a.values = append(a.values, v) }
+
+func (a *otlpArrayEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	nested := &otlpArrayEncoder{}
+	err := arr.MarshalLogArray(nested)
+	a.append(otellog.SliceValue(nested.values...))
+	return err
+}
+
+func (a *otlpArrayEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	nested := &otlpEncoder{}
+	err := obj.MarshalLogObject(nested)
+	a.append(otellog.MapValue(nested.attributes()...))
+	return err
+}
+
+func (a *otlpArrayEncoder) AppendReflected(value any) error {
+	a.append(otlpReflectedValue(value))
+	return nil
+}
+
+func (a *otlpArrayEncoder) AppendBool(v bool) { a.append(otellog.BoolValue(v)) }
+func (a *otlpArrayEncoder) AppendByteString(v []byte) {
+	a.append(otellog.StringValue(string(v)))
+}
+func (a *otlpArrayEncoder) AppendComplex128(v complex128) {
+	a.append(otellog.StringValue(fmt.Sprint(v)))
+}
+func (a *otlpArrayEncoder) AppendComplex64(v complex64) {
+	a.append(otellog.StringValue(fmt.Sprint(v)))
+}
+func (a *otlpArrayEncoder) AppendDuration(v time.Duration) {
+	a.append(otellog.Float64Value(v.Seconds()))
+}
+func (a *otlpArrayEncoder) AppendFloat64(v float64) { a.append(otellog.Float64Value(v)) }
+func (a *otlpArrayEncoder) AppendFloat32(v float32) { a.append(otellog.Float64Value(float64(v))) }
+func (a *otlpArrayEncoder) AppendInt(v int)         { a.append(otellog.IntValue(v)) }
+func (a *otlpArrayEncoder) AppendInt64(v int64)     { a.append(otellog.Int64Value(v)) }
+func (a *otlpArrayEncoder) AppendInt32(v int32)     { a.append(otellog.Int64Value(int64(v))) }
+func (a *otlpArrayEncoder) AppendInt16(v int16)     { a.append(otellog.Int64Value(int64(v))) }
+func (a *otlpArrayEncoder) AppendInt8(v int8)       { a.append(otellog.Int64Value(int64(v))) }
+func (a *otlpArrayEncoder) AppendString(v string)   { a.append(otellog.StringValue(v)) }
+func (a *otlpArrayEncoder) AppendTime(v time.Time) {
+	a.append(otellog.StringValue(v.Format(time.RFC3339Nano)))
+}
+func (a *otlpArrayEncoder) AppendUint(v uint)       { a.append(otlpUint("", uint64(v)).Value) }
+func (a *otlpArrayEncoder) AppendUint64(v uint64)   { a.append(otlpUint("", v).Value) }
+func (a *otlpArrayEncoder) AppendUint32(v uint32)   { a.append(otlpUint("", uint64(v)).Value) }
+func (a *otlpArrayEncoder) AppendUint16(v uint16)   { a.append(otlpUint("", uint64(v)).Value) }
+func (a *otlpArrayEncoder) AppendUint8(v uint8)     { a.append(otlpUint("", uint64(v)).Value) }
+func (a *otlpArrayEncoder) AppendUintptr(v uintptr) { a.append(otlpUint("", uint64(v)).Value) }
+
+var (
+	_ zapcore.ObjectEncoder = (*otlpEncoder)(nil)
+	_ zapcore.ArrayEncoder  = (*otlpArrayEncoder)(nil)
+)
@@ -0,0 +1,263 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver collects exported log requests.
+type otlpReceiver struct {
+	mu       sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+}
+
+func (r *otlpReceiver) add(req *collogspb.ExportLogsServiceRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+}
+
+// records returns the received records with the service.name of their resource.
+func (r *otlpReceiver) records() ([]*logspb.LogRecord, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var (
+		records  []*logspb.LogRecord
+		services []string
+	)
+	for _, req := range r.requests {
+		for _, rl := range req.ResourceLogs {
+			service := attributeValue(rl.Resource.Attributes, "service.name").GetStringValue()
+			for _, sl := range rl.ScopeLogs {
+				for _, rec := range sl.LogRecords {
+					records = append(records, rec)
+					services = append(services, service)
+				}
+			}
+		}
+	}
+	return records, services
+}
+
+// attributeValue returns the value of key in attrs, or nil.
+func attributeValue(attrs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+// newHTTPReceiver starts an OTLP/HTTP receiver.
+func newHTTPReceiver(t *testing.T) (*otlpReceiver, string) {
+	t.Helper()
+	receiver := &otlpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || r.URL.Path != "/v1/logs" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := &collogspb.ExportLogsServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		receiver.add(req)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		data, _ := proto.Marshal(&collogspb.ExportLogsServiceResponse{})
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return receiver, server.URL + "/v1/logs"
+}
+
+// grpcReceiver is an OTLP/gRPC logs service.
+type grpcReceiver struct {
+	collogspb.UnimplementedLogsServiceServer
+	*otlpReceiver
+}
+
+func (r grpcReceiver) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	r.add(req)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+// newGRPCReceiver starts an OTLP/gRPC receiver.
+func newGRPCReceiver(t *testing.T) (*otlpReceiver, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	receiver := &otlpReceiver{}
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, grpcReceiver{otlpReceiver: receiver})
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(server.Stop)
+	return receiver, "http://" + ln.Addr().String()
+}
+
+// TestBuildLogger_OTLP tests export over OTLP/HTTP and OTLP/gRPC.
+func TestBuildLogger_OTLP(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol config.OTLPProtocol
+		receiver func(t *testing.T) (*otlpReceiver, string)
+	}{
+		{name: "http", protocol: config.OTLPProtocolHTTP, receiver: newHTTPReceiver},
+		{name: "grpc", protocol: config.OTLPProtocolGRPC, receiver: newGRPCReceiver},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver, endpoint := tt.receiver(t)
+
+			log, err := buildLogger(config.LoggerConfig{
+				Level:       config.LogLevelDebug,
+				Environment: config.EnvProduction,
+				ServiceName: "otlp-service",
+				Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: t.TempDir() + "/app.log"}}},
+				OTLP:        config.OTLPConfig{Enabled: true, Protocol: tt.protocol, Endpoint: endpoint, Level: config.LogLevelInfo},
+			})
+			if err != nil {
+				t.Fatalf("failed to build logger: %v", err)
+			}
+			defer log.Close()
+
+			spanCtx, end := startTestSpan(t)
+			defer end()
+
+			log.Debug("below OTLP level")
+			log.Named("orders").With(zap.String("tenant", "acme")).Warn("order delayed",
+				zap.Int("attempt", 3),
+				zap.Namespace("order"),
+				zap.String("id", "o-1"),
+			)
+			InfoCtx(NewContext(spanCtx, log), "traced")
+			if err := log.Sync(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			records, services := receiver.records()
+			if len(records) != 2 {
+				t.Fatalf("expected 2 records, got %d", len(records))
+			}
+			sc := trace.SpanContextFromContext(spanCtx)
+			traced := records[1]
+			if traceID := sc.TraceID(); !bytes.Equal(traced.TraceId, traceID[:]) {
+				t.Errorf("expected trace ID %s, got %x", sc.TraceID(), traced.TraceId)
+			}
+			if spanID := sc.SpanID(); !bytes.Equal(traced.SpanId, spanID[:]) {
+				t.Errorf("expected span ID %s, got %x", sc.SpanID(), traced.SpanId)
+			}
+			if len(records[0].TraceId) != 0 {
+				t.Errorf("expected no trace ID without span, got %x", records[0].TraceId)
+			}
+			rec := records[0]
+			if services[0] != "otlp-service" {
+				t.Errorf("expected service.name otlp-service, got %q", services[0])
+			}
+			if rec.Body.GetStringValue() != "order delayed" {
+				t.Errorf("unexpected body: %v", rec.Body)
+			}
+			if rec.SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_WARN || rec.SeverityText != "WARN" {
+				t.Errorf("unexpected severity: %v %q", rec.SeverityNumber, rec.SeverityText)
+			}
+			if got := attributeValue(rec.Attributes, "service").GetStringValue(); got != "otlp-service" {
+				t.Errorf("expected service attribute, got %q", got)
+			}
+			if got := attributeValue(rec.Attributes, "logger").GetStringValue(); got != "orders" {
+				t.Errorf("expected logger attribute, got %q", got)
+			}
+			if got := attributeValue(rec.Attributes, "tenant").GetStringValue(); got != "acme" {
+				t.Errorf("expected tenant attribute, got %q", got)
+			}
+			if got := attributeValue(rec.Attributes, "attempt").GetIntValue(); got != 3 {
+				t.Errorf("expected attempt attribute, got %d", got)
+			}
+			order := attributeValue(rec.Attributes, "order").GetKvlistValue()
+			if got := attributeValue(order.GetValues(), "id").GetStringValue(); got != "o-1" {
+				t.Errorf("expected namespaced id attribute, got %v", order)
+			}
+		})
+	}
+}
+
+// TestOTLPSeverity tests the mapping of zap levels to OpenTelemetry severities.
+func TestOTLPSeverity(t *testing.T) {
+	tests := []struct {
+		level zapcore.Level
+		want  int
+	}{
+		{level: zapcore.DebugLevel, want: 5},
+		{level: zapcore.InfoLevel, want: 9},
+		{level: zapcore.WarnLevel, want: 13},
+		{level: zapcore.ErrorLevel, want: 17},
+		{level: zapcore.DPanicLevel, want: 21},
+		{level: zapcore.PanicLevel, want: 22},
+		{level: zapcore.FatalLevel, want: 23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			if got := int(otlpSeverity(tt.level)); got != tt.want {
+				t.Errorf("otlpSeverity(%v) = %d, want %d", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOTLPEncoder tests the conversion of fields to attributes.
+func TestOTLPEncoder(t *testing.T) {
+	enc := &otlpEncoder{}
+	fields := []zap.Field{
+		zap.String("s", "v"),
+		zap.Bool("b", true),
+		zap.Float64("f", 1.5),
+		zap.Duration("d", 1500*time.Millisecond),
+		zap.Uint64("big", 1<<63),
+		zap.Strings("list", []string{"a", "b"}),
+		zap.Any("obj", map[string]int{"n": 1}),
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	got := map[string]string{}
+	for _, kv := range enc.attributes() {
+		got[kv.Key] = kv.Value.String()
+	}
+	want := map[string]string{
+		"s":    "v",
+		"b":    "true",
+		"f":    "1.5",
+		"d":    "1.5",
+		"big":  "9223372036854775808",
+		"list": "[a b]",
+		"obj":  `{"n":1}`,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("attribute %s = %q, want %q", key, got[key], value)
+		}
+	}
+}
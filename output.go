@@ -0,0 +1,275 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// networkDialTimeout bounds how long a network output waits for a connection.
+	networkDialTimeout = 5 * time.Second
+	// networkRetryMin and networkRetryMax bound the delay between the
+	// background connection attempts of a network output whose endpoint is
+	// down.
+	networkRetryMin = 100 * time.Millisecond
+	networkRetryMax = 30 * time.Second
+)
+
+// buildOutputs creates one core per effective output of cfg, combined with
+// zapcore.NewTee, plus the OTLP exporter when enabled. When async writing is
+// enabled, each output gets its own asyncWriter counting drops in counters.
+// It returns the closers of the outputs it opened; on error, outputs opened
+// so far are closed.
+func buildOutputs(cfg config.LoggerConfig, counters *counters) (zapcore.Core, []io.Closer, error) {
+	var (
+		cores   []zapcore.Core
+		closers []io.Closer
+	)
+	fail := func(err error) (zapcore.Core, []io.Closer, error) {
+		for _, c := range closers {
+			_ = c.Close()
+		}
+		return nil, nil, err
+	}
+
+	for i, out := range cfg.EffectiveOutputs() {
+		// Outputs accept every level by default; the logger level is applied
+		// by levelFilterCore so named loggers can be more verbose than the root.
+		minLevel := zapcore.DebugLevel
+		if out.Level != "" {
+			level, err := toZapLevel(out.Level)
+			if err != nil {
+				return fail(fmt.Errorf("output %d (%s): %w", i, out.Type, err))
+			}
+			minLevel = level
+		}
+
+		ws, closer, err := openOutput(out)
+		if err != nil {
+			return fail(fmt.Errorf("output %d (%s): %w", i, out.Type, err))
+		}
+
+		enc := newOutputEncoder(cfg.Environment, out)
+		if !cfg.Async.Enabled {
+			if closer != nil {
+				closers = append(closers, closer)
+			}
+			cores = append(cores, zapcore.NewCore(enc, ws, minLevel))
+			continue
+		}
+
+		aw, err := newAsyncWriter(ws, cfg.Async, &counters.asyncDropped)
+		if err != nil {
+			if closer != nil {
+				_ = closer.Close()
+			}
+			return fail(fmt.Errorf("output %d (%s): %w", i, out.Type, err))
+		}
+		// The async writer must drain into the output before it is closed.
+		closers = append(closers, aw)
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+		cores = append(cores, newAsyncCore(enc, aw, minLevel))
+	}
+
+	if cfg.OTLP.Enabled {
+		core, closer, err := newOTLPCore(cfg)
+		if err != nil {
+			return fail(fmt.Errorf("otlp: %w", err))
+		}
+		cores = append(cores, core)
+		closers = append(closers, closer)
+	}
+
+	return zapcore.NewTee(cores...), closers, nil
+}
+
+// openOutput opens the destination of out.
+func openOutput(out config.OutputConfig) (zapcore.WriteSyncer, io.Closer, error) {
+	switch out.Type {
+	case config.OutputStdout:
+		return zapcore.AddSync(os.Stdout), nil, nil
+	case config.OutputStderr:
+		return zapcore.AddSync(os.Stderr), nil, nil
+	case config.OutputFile:
+		file, err := newFileSink(out.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		return file, file, nil
+	case config.OutputNetwork:
+		network, address, err := config.ParseNetworkAddress(out.Address)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink := newNetworkSink(network, address, net.DialTimeout)
+		return sink, sink, nil
+	default:
+		return nil, nil, out.Type.Validate()
+	}
+}
+
+// newOutputEncoder returns the encoder of out. Unless overridden, stdout and
+// stderr use the encoding of the environment's preset, with colored levels
+// if the preset enables colors; everything else, including files and network
+// outputs, uses JSON.
+func newOutputEncoder(env config.Environment, out config.OutputConfig) zapcore.Encoder {
+	terminal := out.Type == config.OutputStdout || out.Type == config.OutputStderr
+	preset, _ := env.Preset()
+
+	encoding := out.Encoding
+	if encoding == "" {
+		encoding = config.EncodingJSON
+		if terminal && preset.Encoding != "" {
+			encoding = preset.Encoding
+		}
+	}
+
+	encoderConfig := newEncoderConfig()
+	if encoding == config.EncodingJSON {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+	if terminal && preset.Color {
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	} else {
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
+
+// networkSink is a zapcore.WriteSyncer sending entries to a TCP or UDP
+// endpoint.
+//
+// Connections are established in the background: when the sink is created,
+// and again after a failed write, retrying with exponential backoff until
+// they succeed. Writes never wait for the endpoint; while no connection is
+// available they fail fast and the entries are lost.
+type networkSink struct {
+	network string
+	address string
+	dial    dialFunc
+
+	mu      sync.Mutex
+	conn    net.Conn
+	closed  bool
+	dialing bool
+	dialErr error // last failed attempt, set while reconnecting
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// dialFunc connects to an endpoint, as net.DialTimeout does.
+type dialFunc func(network, address string, timeout time.Duration) (net.Conn, error)
+
+// newNetworkSink creates a sink for the given network and address and starts
+// connecting with dial.
+func newNetworkSink(network, address string, dial dialFunc) *networkSink {
+	s := &networkSink{network: network, address: address, dial: dial, done: make(chan struct{})}
+	s.mu.Lock()
+	s.startDialing()
+	s.mu.Unlock()
+	return s
+}
+
+// Write sends p, or fails fast if no connection is available. A failed
+// write drops the connection and starts reconnecting.
+func (s *networkSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, os.ErrClosed
+	}
+	if s.conn == nil {
+		if s.dialErr != nil {
+			return 0, fmt.Errorf("%s://%s unavailable, reconnecting: %w", s.network, s.address, s.dialErr)
+		}
+		return 0, fmt.Errorf("%s://%s unavailable: connecting", s.network, s.address)
+	}
+
+	n, err := s.conn.Write(p)
+	if err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		s.startDialing()
+	}
+	return n, err
+}
+
+// startDialing starts connecting in the background, unless an attempt is
+// already under way. Callers must hold s.mu.
+func (s *networkSink) startDialing() {
+	if s.dialing || s.closed {
+		return
+	}
+	s.dialing = true
+	s.wg.Add(1)
+	go s.redial()
+}
+
+// redial connects in the background, retrying with exponential backoff,
+// until it succeeds or the sink is closed.
+func (s *networkSink) redial() {
+	defer s.wg.Done()
+	backoff := networkRetryMin
+	for {
+		conn, err := s.dial(s.network, s.address, networkDialTimeout)
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		if err == nil {
+			s.conn, s.dialErr, s.dialing = conn, nil, false
+			s.mu.Unlock()
+			return
+		}
+		s.dialErr = err
+		s.mu.Unlock()
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, networkRetryMax)
+	}
+}
+
+// Sync is a no-op: entries are sent as they are written.
+func (s *networkSink) Sync() error {
+	return nil
+}
+
+// Close closes the connection and stops reconnecting. Subsequent writes fail
+// with os.ErrClosed.
+func (s *networkSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	var err error
+	if s.conn != nil {
+		err = s.conn.Close()
+		s.conn = nil
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
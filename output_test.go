@@ -0,0 +1,362 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// readLines returns the lines of the file at path.
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// TestBuildLogger_Outputs tests per-output levels and encodings.
+func TestBuildLogger_Outputs(t *testing.T) {
+	dir := t.TempDir()
+	debugPath := filepath.Join(dir, "debug.json")
+	errorPath := filepath.Join(dir, "error.log")
+
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelDebug,
+		Environment: config.EnvDevelopment,
+		ServiceName: "outputs-service",
+		Outputs: []config.OutputConfig{
+			{Type: config.OutputFile, File: config.FileConfig{Path: debugPath}},
+			{Type: config.OutputFile, Level: config.LogLevelError, Encoding: config.EncodingConsole, File: config.FileConfig{Path: errorPath}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	log.Debug("debug entry")
+	log.Info("info entry")
+	log.Error("error entry")
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	debugLines := readLines(t, debugPath)
+	if len(debugLines) != 3 {
+		t.Fatalf("expected 3 JSON entries, got %d: %v", len(debugLines), debugLines)
+	}
+	for _, line := range debugLines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Errorf("expected JSON entry, got %q", line)
+		}
+	}
+
+	errorLines := readLines(t, errorPath)
+	if len(errorLines) == 0 || !strings.Contains(errorLines[0], "ERROR\t") || !strings.Contains(errorLines[0], "error entry") {
+		t.Fatalf("expected console error entry, got %v", errorLines)
+	}
+	for _, line := range errorLines {
+		if strings.Contains(line, "info entry") || strings.Contains(line, "debug entry") {
+			t.Errorf("entry below output level written: %q", line)
+		}
+		if strings.Contains(line, "\x1b[") {
+			t.Errorf("file output should not be colored: %q", line)
+		}
+	}
+}
+
+// TestBuildLogger_OutputsHonorLoggerLevel tests that per-output levels do not
+// bypass the logger level.
+func TestBuildLogger_OutputsHonorLoggerLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelWarn,
+		Environment: config.EnvProduction,
+		ServiceName: "outputs-service",
+		Outputs: []config.OutputConfig{
+			{Type: config.OutputFile, Level: config.LogLevelDebug, File: config.FileConfig{Path: path}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	log.Info("suppressed")
+	log.Warn("written")
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 1 || !strings.Contains(lines[0], "written") {
+		t.Errorf("expected only the WARN entry, got %v", lines)
+	}
+}
+
+// waitConnected waits for the network outputs of log to connect.
+func waitConnected(t *testing.T, log *Logger) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for _, c := range log.current().closers {
+		sink, ok := c.(*networkSink)
+		if !ok {
+			continue
+		}
+		for {
+			sink.mu.Lock()
+			connected := sink.conn != nil
+			sink.mu.Unlock()
+			if connected {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for the network output to connect")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// TestBuildLogger_NetworkOutput tests TCP and UDP outputs.
+func TestBuildLogger_NetworkOutput(t *testing.T) {
+	t.Run("tcp", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer ln.Close()
+
+		received := make(chan string, 1)
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			received <- line
+		}()
+
+		log, err := buildLogger(config.LoggerConfig{
+			Level:       config.LogLevelInfo,
+			Environment: config.EnvProduction,
+			ServiceName: "network-service",
+			Outputs:     []config.OutputConfig{{Type: config.OutputNetwork, Address: "tcp://" + ln.Addr().String()}},
+		})
+		if err != nil {
+			t.Fatalf("failed to build logger: %v", err)
+		}
+		defer log.Close()
+
+		waitConnected(t, log)
+		log.Info("over tcp")
+
+		select {
+		case line := <-received:
+			var entry map[string]any
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("expected JSON entry, got %q", line)
+			}
+			if entry["message"] != "over tcp" {
+				t.Errorf("unexpected entry: %v", entry)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for entry")
+		}
+	})
+
+	t.Run("udp", func(t *testing.T) {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer pc.Close()
+
+		log, err := buildLogger(config.LoggerConfig{
+			Level:       config.LogLevelInfo,
+			Environment: config.EnvProduction,
+			ServiceName: "network-service",
+			Outputs:     []config.OutputConfig{{Type: config.OutputNetwork, Address: "udp://" + pc.LocalAddr().String()}},
+		})
+		if err != nil {
+			t.Fatalf("failed to build logger: %v", err)
+		}
+		defer log.Close()
+
+		waitConnected(t, log)
+		log.Info("over udp")
+
+		buf := make([]byte, 64*1024)
+		_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("failed to read datagram: %v", err)
+		}
+		if !strings.Contains(string(buf[:n]), `"message":"over udp"`) {
+			t.Errorf("unexpected datagram: %q", buf[:n])
+		}
+	})
+}
+
+// TestNetworkSink_Close tests that writes fail after Close.
+func TestNetworkSink_Close(t *testing.T) {
+	s := newNetworkSink("tcp", "127.0.0.1:1", net.DialTimeout)
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Write([]byte("late\n")); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+// TestNetworkSink_Reconnect tests that writes fail fast while the endpoint
+// is down and resume once the sink has reconnected in the background.
+func TestNetworkSink_Reconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	var (
+		mu      sync.Mutex
+		dials   int
+		down    = true
+		dialing = make(chan struct{}, 1)
+	)
+	s := newNetworkSink("tcp", ln.Addr().String(), func(network, address string, timeout time.Duration) (net.Conn, error) {
+		mu.Lock()
+		dials++
+		failing := down
+		mu.Unlock()
+		if failing {
+			select {
+			case dialing <- struct{}{}:
+			default:
+			}
+			time.Sleep(200 * time.Millisecond) // a slow, failing connection attempt
+			return nil, errors.New("connection refused")
+		}
+		return net.DialTimeout(network, address, timeout)
+	})
+	defer s.Close()
+	<-dialing
+
+	if _, err := s.Write([]byte("first\n")); err == nil {
+		t.Fatal("expected error while the endpoint is down")
+	}
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if _, err := s.Write([]byte("lost\n")); err == nil {
+			t.Fatal("expected error while the endpoint is down")
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected writes to fail fast, took %v", elapsed)
+	}
+	mu.Lock()
+	if dials > 2 {
+		t.Errorf("expected writes not to attempt connections, got %d attempts", dials)
+	}
+	down = false
+	mu.Unlock()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := s.Write([]byte("back\n")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the sink to reconnect")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	select {
+	case line := <-received:
+		if line != "back\n" {
+			t.Errorf("unexpected line %q", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for entry")
+	}
+}
+
+// TestNewOutputEncoder tests the default encoding of each output type.
+func TestNewOutputEncoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      config.Environment
+		output   config.OutputConfig
+		wantJSON bool
+	}{
+		{name: "stdout in development", env: config.EnvDevelopment, output: config.OutputConfig{Type: config.OutputStdout}, wantJSON: false},
+		{name: "stdout in production", env: config.EnvProduction, output: config.OutputConfig{Type: config.OutputStdout}, wantJSON: true},
+		{name: "stderr in development", env: config.EnvDevelopment, output: config.OutputConfig{Type: config.OutputStderr}, wantJSON: false},
+		{name: "file in development", env: config.EnvDevelopment, output: config.OutputConfig{Type: config.OutputFile}, wantJSON: true},
+		{name: "network in development", env: config.EnvDevelopment, output: config.OutputConfig{Type: config.OutputNetwork}, wantJSON: true},
+		{name: "explicit console in production", env: config.EnvProduction, output: config.OutputConfig{Type: config.OutputStdout, Encoding: config.EncodingConsole}, wantJSON: false},
+		{name: "explicit JSON in development", env: config.EnvDevelopment, output: config.OutputConfig{Type: config.OutputStdout, Encoding: config.EncodingJSON}, wantJSON: true},
+		{name: "stdout in test", env: config.EnvTest, output: config.OutputConfig{Type: config.OutputStdout}, wantJSON: false},
+		{name: "stdout in staging", env: config.EnvStaging, output: config.OutputConfig{Type: config.OutputStdout}, wantJSON: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := newOutputEncoder(tt.env, tt.output)
+			buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Message: "hello"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer buf.Free()
+
+			isJSON := json.Valid(buf.Bytes())
+			if isJSON != tt.wantJSON {
+				t.Errorf("JSON output = %v, want %v: %q", isJSON, tt.wantJSON, buf.String())
+			}
+		})
+	}
+}
+
+// TestBuildOutputs_Error tests that invalid outputs are reported.
+func TestBuildOutputs_Error(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	_, _, err := buildOutputs(config.LoggerConfig{
+		Environment: config.EnvProduction,
+		Outputs: []config.OutputConfig{
+			{Type: config.OutputFile, File: config.FileConfig{Path: filepath.Join(dir, "ok.log")}},
+			{Type: config.OutputFile, File: config.FileConfig{Path: filepath.Join(blocker, "app.log")}},
+		},
+	}, &counters{})
+	if err == nil || !strings.Contains(err.Error(), "output 1 (file)") {
+		t.Errorf("expected error for output 1, got: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// rateLimitCore drops the entries of a call site or named logger in excess
+// of its token buckets.
+//
+// Call sites are only known once the entry is written, so limited entries
+// are decided in Write; entries that are not limited skip the core.
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+}
+
+// rateLimiter holds the token buckets shared by the cores derived from the
+// same root.
+type rateLimiter struct {
+	by     config.RateLimitKey
+	exempt bool
+	// limits holds the limit of each level, indexed from zapcore.DebugLevel.
+	limits  []levelLimit
+	dropped *atomic.Uint64
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*tokenBucket
+	lastSweep time.Time
+}
+
+// levelLimit is the limit applied to a level and the bucket level it
+// draws from: the level itself, or ERROR for the levels above it.
+type levelLimit struct {
+	config.RateLimit
+	bucket zapcore.Level
+}
+
+// bucketIdleWindow is how long a bucket stays full and unused before it is
+// forgotten, and how often such buckets are looked for.
+const bucketIdleWindow = time.Minute
+
+// bucketKey identifies a bucket: a call site or logger name, and a bucket level.
+type bucketKey struct {
+	key   string
+	level zapcore.Level
+}
+
+// tokenBucket holds the tokens left to a bucket as of last.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimitCore wraps core with the rate limits of cfg, counting the
+// entries it drops in dropped.
+func newRateLimitCore(core zapcore.Core, cfg config.RateLimitConfig, dropped *atomic.Uint64) zapcore.Core {
+	limiter := &rateLimiter{
+		by:      cfg.By,
+		exempt:  cfg.ExemptErrors,
+		dropped: dropped,
+		buckets: make(map[bucketKey]*tokenBucket),
+	}
+	if limiter.by == "" {
+		limiter.by = config.RateLimitByCaller
+	}
+
+	// Each level has its own buckets. Levels above ERROR share the limit
+	// and buckets of ERROR.
+	for lvl := zapcore.DebugLevel; lvl <= zapcore.FatalLevel; lvl++ {
+		bucket := min(lvl, zapcore.ErrorLevel)
+		limit, _ := cfg.Limit(fromZapLevel(bucket))
+		if limit.Burst == 0 {
+			limit.Burst = max(1, int(math.Ceil(limit.Rate)))
+		}
+		limiter.limits = append(limiter.limits, levelLimit{RateLimit: limit, bucket: bucket})
+	}
+	return &rateLimitCore{Core: core, limiter: limiter}
+}
+
+// With adds structured context to the wrapped core, keeping the buckets.
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+// Check passes entries that are not limited to the wrapped core, and adds
+// the core to ce for the others.
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.limiter.limited(ent.Level) {
+		return c.Core.Check(ent, ce)
+	}
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write writes the entry if its bucket has a token left.
+func (c *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.limiter.allow(ent) {
+		return writeChecked(c.Core, ent, fields)
+	}
+	return nil
+}
+
+// limited reports whether entries at lvl are subject to a limit.
+func (l *rateLimiter) limited(lvl zapcore.Level) bool {
+	if lvl < zapcore.DebugLevel || lvl > zapcore.FatalLevel {
+		return false
+	}
+	if l.exempt && lvl >= zapcore.ErrorLevel {
+		return false
+	}
+	return l.limits[lvl-zapcore.DebugLevel].Rate > 0
+}
+
+// allow takes a token from the bucket of the entry, reporting whether there
+// was one.
+func (l *rateLimiter) allow(ent zapcore.Entry) bool {
+	limit := l.limits[ent.Level-zapcore.DebugLevel]
+	key := bucketKey{key: ent.LoggerName, level: limit.bucket}
+	if l.by == config.RateLimitByCaller && ent.Caller.Defined {
+		key.key = fmt.Sprintf("%s:%d", ent.Caller.File, ent.Caller.Line)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(ent.Time)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit.Burst), last: ent.Time}
+		l.buckets[key] = b
+	}
+	if elapsed := ent.Time.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.last = ent.Time
+	}
+	if b.tokens < 1 {
+		l.dropped.Add(1)
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep forgets, at most once per bucketIdleWindow, the buckets unused for
+// bucketIdleWindow that have refilled: a new bucket would be the same. The
+// caller must hold l.mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketIdleWindow {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		limit := l.limits[key.level-zapcore.DebugLevel]
+		idle := now.Sub(b.last)
+		if idle >= bucketIdleWindow && b.tokens+idle.Seconds()*limit.Rate >= float64(limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
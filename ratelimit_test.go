@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// manualClock is a zapcore.Clock advanced by tests.
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time                         { return c.now }
+func (c *manualClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+// newRateLimitedLogger returns a logger limited by cfg, the entries it
+// writes and its clock.
+func newRateLimitedLogger(cfg config.RateLimitConfig) (*zap.Logger, *observer.ObservedLogs, *manualClock, *atomic.Uint64) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	var dropped atomic.Uint64
+	clock := &manualClock{now: time.Now()}
+	log := zap.New(newRateLimitCore(observed, cfg, &dropped), zap.AddCaller(), zap.WithClock(clock))
+	return log, logs, clock, &dropped
+}
+
+// TestRateLimitCore tests that each call site or logger has its own buckets.
+func TestRateLimitCore(t *testing.T) {
+	t.Run("per call site", func(t *testing.T) {
+		log, logs, _, dropped := newRateLimitedLogger(config.RateLimitConfig{Enabled: true, Rate: 1, Burst: 3})
+
+		for i := 0; i < 10; i++ {
+			log.Info("noisy")
+			log.Info("quiet")
+		}
+		if got := logs.FilterMessage("noisy").Len(); got != 3 {
+			t.Errorf("expected 3 noisy entries, got %d", got)
+		}
+		if got := logs.FilterMessage("quiet").Len(); got != 3 {
+			t.Errorf("expected the quiet call site to keep its budget, got %d", got)
+		}
+		if got := dropped.Load(); got != 14 {
+			t.Errorf("expected 14 dropped entries, got %d", got)
+		}
+	})
+
+	t.Run("per logger", func(t *testing.T) {
+		log, logs, _, _ := newRateLimitedLogger(config.RateLimitConfig{Enabled: true, By: config.RateLimitByLogger, Rate: 1, Burst: 2})
+
+		for i := 0; i < 5; i++ {
+			log.Named("db").Info("query")
+			log.Named("db").Info("slow query")
+			log.Named("http").Info("request")
+		}
+		counts := make(map[string]int)
+		for _, entry := range logs.All() {
+			counts[entry.LoggerName]++
+		}
+		if counts["db"] != 2 || counts["http"] != 2 {
+			t.Errorf("expected 2 entries per logger, got %v", counts)
+		}
+	})
+
+	t.Run("per level", func(t *testing.T) {
+		log, logs, _, _ := newRateLimitedLogger(config.RateLimitConfig{
+			Enabled: true,
+			Rate:    5,
+			Levels:  map[config.LogLevel]config.RateLimit{config.LogLevelDebug: {Rate: 1}},
+		})
+
+		for i := 0; i < 10; i++ {
+			log.Debug("loop")
+			log.Info("loop")
+			log.Warn("loop")
+		}
+		want := map[zapcore.Level]int{zapcore.DebugLevel: 1, zapcore.InfoLevel: 5, zapcore.WarnLevel: 5}
+		for level, n := range want {
+			if got := logs.FilterLevelExact(level).Len(); got != n {
+				t.Errorf("%s: expected %d entries, got %d", level, n, got)
+			}
+		}
+	})
+
+	t.Run("per level by default", func(t *testing.T) {
+		log, logs, _, _ := newRateLimitedLogger(config.RateLimitConfig{Enabled: true, By: config.RateLimitByLogger, Rate: 1, Burst: 2})
+
+		for i := 0; i < 5; i++ {
+			log.Debug("loop")
+			log.Info("loop")
+		}
+		if got := logs.FilterLevelExact(zapcore.DebugLevel).Len(); got != 2 {
+			t.Errorf("expected 2 DEBUG entries, got %d", got)
+		}
+		if got := logs.FilterLevelExact(zapcore.InfoLevel).Len(); got != 2 {
+			t.Errorf("expected DEBUG entries not to use up the INFO budget, got %d", got)
+		}
+	})
+
+	t.Run("exempt errors", func(t *testing.T) {
+		log, logs, _, _ := newRateLimitedLogger(config.RateLimitConfig{Enabled: true, Rate: 1, ExemptErrors: true})
+
+		for i := 0; i < 5; i++ {
+			log.Warn("loop")
+			log.Error("loop")
+		}
+		if got := logs.FilterLevelExact(zapcore.WarnLevel).Len(); got != 1 {
+			t.Errorf("expected 1 WARN entry, got %d", got)
+		}
+		if got := logs.FilterLevelExact(zapcore.ErrorLevel).Len(); got != 5 {
+			t.Errorf("expected every ERROR entry, got %d", got)
+		}
+	})
+}
+
+// TestRateLimitCore_Refill tests that buckets refill at the configured rate.
+func TestRateLimitCore_Refill(t *testing.T) {
+	log, logs, clock, _ := newRateLimitedLogger(config.RateLimitConfig{Enabled: true, Rate: 2, Burst: 2})
+	logLoop := func() {
+		for i := 0; i < 5; i++ {
+			log.Info("loop")
+		}
+	}
+
+	logLoop()
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	logLoop()
+	clock.now = clock.now.Add(time.Hour)
+	logLoop()
+
+	// The burst, one token after half a second, then a full bucket.
+	if got := logs.Len(); got != 2+1+2 {
+		t.Errorf("expected 5 entries, got %d", got)
+	}
+}
+
+// TestRateLimitCore_Eviction tests that idle, refilled buckets are forgotten.
+func TestRateLimitCore_Eviction(t *testing.T) {
+	log, _, clock, _ := newRateLimitedLogger(config.RateLimitConfig{Enabled: true, By: config.RateLimitByLogger, Rate: 1, Burst: 5})
+	limiter := log.Core().(*rateLimitCore).limiter
+	buckets := func() int {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return len(limiter.buckets)
+	}
+
+	for i := 0; i < 100; i++ {
+		log.Named(strconv.Itoa(i)).Info("once")
+	}
+	if got := buckets(); got != 100 {
+		t.Fatalf("expected 100 buckets, got %d", got)
+	}
+
+	clock.now = clock.now.Add(bucketIdleWindow)
+	for i := 0; i < 10; i++ {
+		log.Named("busy").Info("again")
+	}
+	if got := buckets(); got != 1 {
+		t.Errorf("expected only the busy bucket to be kept, got %d", got)
+	}
+
+	// The busy bucket was emptied, but has refilled since.
+	clock.now = clock.now.Add(bucketIdleWindow)
+	log.Named("other").Info("once")
+	if got := buckets(); got != 1 {
+		t.Errorf("expected the refilled busy bucket to be forgotten, got %d", got)
+	}
+}
+
+// TestBuildLogger_RateLimit tests that rate limiting from the configuration
+// is applied and reported in Stats.
+func TestBuildLogger_RateLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+		Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+		RateLimit:   config.RateLimitConfig{Enabled: true, Rate: 0.001, Burst: 4},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		log.With(zap.Int("attempt", i)).Info("retrying")
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lines := readLines(t, path); len(lines) != 4 {
+		t.Errorf("expected 4 entries, got %d", len(lines))
+	}
+	if got := log.Stats().RateLimited; got != 6 {
+		t.Errorf("expected 6 rate limited entries, got %d", got)
+	}
+}
+
+// TestNew_RateLimitByCaller tests that the call sites of direct and
+// package-level calls get buckets of their own.
+func TestNew_RateLimitByCaller(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := New(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+		Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+		RateLimit:   config.RateLimitConfig{Enabled: true, By: config.RateLimitByCaller, Rate: 0.001, Burst: 1},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+	restore := ReplaceGlobal(log)
+	defer restore()
+
+	for i := 0; i < 3; i++ {
+		log.Info("direct")
+		log.Info("direct")
+		Info("global")
+		Info("global")
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 4 {
+		t.Fatalf("expected one entry per call site, got %d: %v", len(lines), lines)
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "ratelimit_test.go:") {
+			t.Errorf("expected caller in ratelimit_test.go, got %s", line)
+		}
+	}
+	if got := log.Stats().RateLimited; got != 8 {
+		t.Errorf("expected 8 rate limited entries, got %d", got)
+	}
+}
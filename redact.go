@@ -0,0 +1,433 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue replaces values under RedactReplace.
+const redactedValue = "[REDACTED]"
+
+// redactor rewrites the sensitive values of fields and messages according
+// to compiled redaction rules, and replaces the values of Token fields with
+// their token.
+type redactor struct {
+	rules  []redactRule
+	tokens *tokenizer
+}
+
+// redactRule is a compiled config.RedactionRule.
+type redactRule struct {
+	keys        map[string]bool
+	keyPatterns []string
+	values      []valueMatcher
+	action      config.RedactAction
+	tokens      *tokenizer
+}
+
+// valueMatcher matches sensitive parts of string values.
+type valueMatcher struct {
+	re *regexp.Regexp
+	// luhn discards matches failing the Luhn check, for card numbers.
+	luhn bool
+}
+
+// buildRedactor returns the redactor of the configuration, or nil if
+// neither redaction nor tokenization is configured.
+func buildRedactor(cfg config.LoggerConfig) (*redactor, error) {
+	tokens, err := newTokenizer(cfg.Tokenization)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Redaction.Enabled && tokens == nil {
+		return nil, nil
+	}
+	return newRedactor(cfg.Redaction, tokens)
+}
+
+// newRedactor compiles the rules of cfg, if enabled. tokens produces the
+// tokens of Token fields and of RedactTokenize rules; it may be nil if
+// there are none.
+func newRedactor(cfg config.RedactionConfig, tokens *tokenizer) (*redactor, error) {
+	r := &redactor{tokens: tokens}
+	if !cfg.Enabled {
+		return r, nil
+	}
+	for i, rule := range cfg.EffectiveRules() {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("redaction rule %d: %w", i, err)
+		}
+		if rule.Action == config.RedactTokenize && tokens == nil {
+			return nil, fmt.Errorf("redaction rule %d: %w: the tokenize action needs a tokenization key", i, config.ErrInvalidValue)
+		}
+		compiled := redactRule{keys: make(map[string]bool), action: rule.Action, tokens: tokens}
+		if compiled.action == "" {
+			compiled.action = config.RedactReplace
+		}
+		for _, key := range rule.Keys {
+			compiled.keys[strings.ToLower(key)] = true
+		}
+		for _, pattern := range rule.KeyPatterns {
+			compiled.keyPatterns = append(compiled.keyPatterns, strings.ToLower(pattern))
+		}
+		for _, pattern := range rule.ValuePatterns {
+			compiled.values = append(compiled.values, valueMatcher{
+				re:   regexp.MustCompile(config.ValuePattern(pattern)),
+				luhn: pattern == config.PatternCreditCard,
+			})
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	return r, nil
+}
+
+// keyRule returns the first rule selecting the field name key, or nil.
+func (r *redactor) keyRule(key string) *redactRule {
+	key = strings.ToLower(key)
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.keys[key] {
+			return rule
+		}
+		for _, pattern := range rule.keyPatterns {
+			if ok, _ := path.Match(pattern, key); ok {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// redactString rewrites the parts of s matched by value patterns, reporting
+// whether any was.
+func (r *redactor) redactString(s string) (string, bool) {
+	changed := false
+	for i := range r.rules {
+		rule := &r.rules[i]
+		for _, m := range rule.values {
+			s = m.re.ReplaceAllStringFunc(s, func(match string) string {
+				if m.luhn && !luhnValid(match) {
+					return match
+				}
+				changed = true
+				return rule.rewrite(match)
+			})
+		}
+	}
+	return s, changed
+}
+
+// fields returns fields with sensitive values rewritten. fields itself is
+// returned if nothing was rewritten.
+func (r *redactor) fields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		redacted, changed := r.field(f)
+		if changed && out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields[:i])
+		}
+		if out != nil {
+			out[i] = redacted
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// field rewrites the sensitive values of f, reporting whether any was.
+func (r *redactor) field(f zapcore.Field) (zapcore.Field, bool) {
+	if f.Type == zapcore.NamespaceType || f.Type == zapcore.SkipType {
+		return f, false
+	}
+	if value, ok := f.Interface.(tokenValue); ok && f.Type == zapcore.StringerType {
+		if r.tokens == nil {
+			return zap.String(f.Key, redactedValue), true
+		}
+		return zap.String(f.Key, r.tokens.token(string(value))), true
+	}
+	if rule := r.keyRule(f.Key); rule != nil && f.Type != zapcore.InlineMarshalerType {
+		return zap.String(f.Key, rule.rewrite(fieldString(f))), true
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if s, ok := r.redactString(f.String); ok {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ByteStringType:
+		if s, ok := r.redactString(string(f.Interface.([]byte))); ok {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ErrorType, zapcore.StringerType:
+		if s, ok := r.redactString(fieldString(f)); ok {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.ReflectType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if v, ok := r.value(enc.Fields[f.Key]); ok {
+			return redactedField(f.Key, v), true
+		}
+	case zapcore.InlineMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if v, ok := r.value(enc.Fields); ok {
+			return zap.Inline(redactedObject(v.(map[string]any))), true
+		}
+	}
+	return f, false
+}
+
+// value rewrites the sensitive values in v, as produced by a
+// zapcore.MapObjectEncoder, reporting whether any was. Objects and arrays
+// are traversed; other values without a known representation are
+// traversed in their JSON form.
+func (r *redactor) value(v any) (any, bool) {
+	switch x := v.(type) {
+	case map[string]any:
+		// The map belongs to the caller: rewrite a copy, made on the first
+		// change.
+		var out map[string]any
+		for key, elem := range x {
+			var redacted any
+			if rule := r.keyRule(key); rule != nil {
+				redacted = rule.rewrite(valueString(elem))
+			} else if v, ok := r.value(elem); ok {
+				redacted = v
+			} else {
+				continue
+			}
+			if out == nil {
+				out = maps.Clone(x)
+			}
+			out[key] = redacted
+		}
+		if out == nil {
+			return x, false
+		}
+		return out, true
+	case []any:
+		var out []any
+		for i, elem := range x {
+			if redacted, ok := r.value(elem); ok {
+				if out == nil {
+					out = slices.Clone(x)
+				}
+				out[i] = redacted
+			}
+		}
+		if out == nil {
+			return x, false
+		}
+		return out, true
+	case string:
+		return r.redactString(x)
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128, []byte:
+		return v, false
+	}
+
+	// Reflected values, such as structs and maps passed to zap.Any.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v, false
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return v, false
+	}
+	if redacted, ok := r.value(generic); ok {
+		return redacted, true
+	}
+	return v, false
+}
+
+// rewrite applies the action of the rule to a sensitive value.
+func (rule *redactRule) rewrite(value string) string {
+	switch rule.action {
+	case config.RedactMask:
+		return mask(value)
+	case config.RedactHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	case config.RedactTokenize:
+		if rule.tokens != nil {
+			return rule.tokens.token(value)
+		}
+		return redactedValue
+	default:
+		return redactedValue
+	}
+}
+
+// mask replaces the characters of value with '*', keeping the last four of
+// values longer than eight characters.
+func mask(value string) string {
+	runes := []rune(value)
+	keep := 0
+	if len(runes) > 8 {
+		keep = 4
+	}
+	for i := 0; i < len(runes)-keep; i++ {
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+// luhnValid reports whether the digits of s, ignoring separators, form a
+// card number passing the Luhn check.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// fieldString returns the string form of the value of f, as encoded in
+// entries.
+func fieldString(f zapcore.Field) (s string) {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return err.Error()
+		}
+	case zapcore.StringerType:
+		// Stringers may panic, e.g. on nil pointers; zap encodes the panic
+		// instead, so the value is treated as empty.
+		defer func() {
+			if recover() != nil {
+				s = ""
+			}
+		}()
+		if stringer, ok := f.Interface.(fmt.Stringer); ok {
+			return stringer.String()
+		}
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return valueString(enc.Fields[f.Key])
+}
+
+// valueString returns the string form of a value produced by a
+// zapcore.MapObjectEncoder: strings as is, other values in JSON.
+func valueString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// redactedField returns a field holding a redacted value.
+func redactedField(key string, v any) zapcore.Field {
+	switch x := v.(type) {
+	case map[string]any:
+		return zap.Object(key, redactedObject(x))
+	case []any:
+		return zap.Array(key, redactedArray(x))
+	default:
+		return zap.Any(key, x)
+	}
+}
+
+// redactedObject encodes a redacted object, with its keys sorted.
+type redactedObject map[string]any
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(o))
+	for key := range o {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		redactedField(key, o[key]).AddTo(enc)
+	}
+	return nil
+}
+
+// redactedArray encodes a redacted array.
+type redactedArray []any
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (a redactedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, elem := range a {
+		var err error
+		switch x := elem.(type) {
+		case map[string]any:
+			err = enc.AppendObject(redactedObject(x))
+		case []any:
+			err = enc.AppendArray(redactedArray(x))
+		default:
+			err = enc.AppendReflected(x)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redactCore rewrites the sensitive values of entries before passing them
+// to the wrapped core.
+type redactCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+// newRedactCore wraps core with the redaction of r.
+func newRedactCore(core zapcore.Core, r *redactor) zapcore.Core {
+	return &redactCore{Core: core, redactor: r}
+}
+
+// With redacts the context fields before adding them to the wrapped core.
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redactor.fields(fields)), redactor: c.redactor}
+}
+
+// Check adds the core to ce if the wrapped core is enabled for the entry.
+// The wrapped core is checked again on Write, with the redacted entry.
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write redacts the message and fields of the entry and writes it.
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message, _ = c.redactor.redactString(ent.Message)
+	return writeChecked(c.Core, ent, c.redactor.fields(fields))
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// sampledLevels are the levels whose entries are sampled. Entries above
+// ERROR are always logged.
+var sampledLevels = []config.LogLevel{
+	config.LogLevelDebug,
+	config.LogLevelInfo,
+	config.LogLevelWarn,
+	config.LogLevelError,
+}
+
+// samplingCore samples the entries of each level with the policy of that
+// level, and passes the other entries to the wrapped core unsampled.
+type samplingCore struct {
+	zapcore.Core
+	// samplers holds a sampler over Core per sampled level, indexed from
+	// zapcore.DebugLevel.
+	samplers []zapcore.Core
+}
+
+// newSamplingCore wraps core with the sampling policies of cfg, counting
+// the entries it drops in dropped.
+func newSamplingCore(core zapcore.Core, cfg config.SamplingConfig, dropped *atomic.Uint64) zapcore.Core {
+	hook := zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+		if dec&zapcore.LogDropped != 0 {
+			dropped.Add(1)
+		}
+	})
+
+	samplers := make([]zapcore.Core, len(sampledLevels))
+	for i, level := range sampledLevels {
+		policy := cfg.Policy(level)
+		samplers[i] = zapcore.NewSamplerWithOptions(core, policy.Tick, policy.Initial, policy.Thereafter, hook)
+	}
+	return &samplingCore{Core: core, samplers: samplers}
+}
+
+// sampler returns the sampler for lvl, or nil if lvl is not sampled.
+func (c *samplingCore) sampler(lvl zapcore.Level) zapcore.Core {
+	if i := int(lvl - zapcore.DebugLevel); i >= 0 && i < len(c.samplers) {
+		return c.samplers[i]
+	}
+	return nil
+}
+
+// With adds structured context to the wrapped core and to the samplers,
+// which keep sharing their counts with this core.
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	samplers := make([]zapcore.Core, len(c.samplers))
+	for i, s := range c.samplers {
+		samplers[i] = s.With(fields)
+	}
+	return &samplingCore{Core: c.Core.With(fields), samplers: samplers}
+}
+
+// Check delegates to the sampler of the entry's level, or to the wrapped
+// core for levels that are not sampled.
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if s := c.sampler(ent.Level); s != nil {
+		return s.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
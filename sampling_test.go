@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestSamplingCore tests that each level is sampled with its own policy.
+func TestSamplingCore(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	var dropped atomic.Uint64
+	core := newSamplingCore(observed, config.SamplingConfig{
+		Enabled:    true,
+		Tick:       time.Hour,
+		Initial:    5,
+		Thereafter: 10,
+		Levels: map[config.LogLevel]config.SamplingPolicy{
+			config.LogLevelDebug: {Initial: 1, Thereafter: 1000},
+		},
+	}, &dropped)
+	log := zap.New(core)
+	child := log.With(zap.String("request_id", "abc"))
+
+	for i := 0; i < 50; i++ {
+		log.Debug("debug loop")
+		child.Info("info loop")
+		log.DPanic("never sampled")
+	}
+
+	counts := make(map[zapcore.Level]int)
+	for _, entry := range logs.All() {
+		counts[entry.Level]++
+	}
+	// DEBUG: the first entry only. INFO: 5 entries, then every 10th of the
+	// remaining 45.
+	want := map[zapcore.Level]int{zapcore.DebugLevel: 1, zapcore.InfoLevel: 9, zapcore.DPanicLevel: 50}
+	for level, n := range want {
+		if counts[level] != n {
+			t.Errorf("%s: expected %d entries, got %d", level, n, counts[level])
+		}
+	}
+	if got := dropped.Load(); got != 49+41 {
+		t.Errorf("expected %d dropped entries, got %d", 49+41, got)
+	}
+	if fields := logs.FilterLevelExact(zapcore.InfoLevel).All()[0].ContextMap(); fields["request_id"] != "abc" {
+		t.Errorf("expected context field on sampled entries, got %v", fields)
+	}
+}
+
+// TestBuildLogger_Sampling tests that sampling from the configuration is
+// applied and reported in Stats.
+func TestBuildLogger_Sampling(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "test-service",
+		Outputs:     []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+		Sampling:    config.SamplingConfig{Enabled: true, Tick: time.Hour, Initial: 3, Thereafter: 1000},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+	defer log.Close()
+
+	for i := 0; i < 20; i++ {
+		log.Named("worker").Error("hot loop")
+	}
+	if err := log.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lines := readLines(t, path); len(lines) != 3 {
+		t.Errorf("expected 3 entries, got %d", len(lines))
+	}
+	if got := log.Stats().SampledDropped; got != 17 {
+		t.Errorf("expected 17 sampled out entries, got %d", got)
+	}
+}
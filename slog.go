@@ -0,0 +1,226 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler is a slog.Handler writing records into a Logger's core.
+//
+// slog groups map to zap namespaces. Groups are opened lazily, so a group
+// without attributes does not appear in the output, as slog requires.
+// Attributes added before the first group are applied to the core; those
+// added within groups are kept pending and written with each record, after
+// the context fields, so that context fields such as trace_id stay at the
+// top level.
+type slogHandler struct {
+	log  *Logger
+	core zapcore.Core
+	// pending holds the attributes added within groups, with the namespaces
+	// opening their groups.
+	pending []zap.Field
+	// groups are the groups opened after the last pending attributes.
+	groups []string
+}
+
+// SlogHandler returns a slog.Handler writing into this logger's core.
+//
+// Records go through the same outputs, level (including runtime changes made
+// with SetLevel) and fields as the logger, including the service field and
+// the logger name. slog levels map to the closest zap level at or below
+// them, e.g. slog.LevelWarn+2 is logged as WARN. The context passed to the
+// slog methods contributes its span context and ContextWithFields fields, as
+// with InfoCtx.
+//
+// Example:
+//
+//	handler := logger.Get().Named("db").SlogHandler()
+//	slog.New(handler).Info("connected", "host", host)
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{log: l, core: l.Logger.Core()}
+}
+
+// Slog returns a *slog.Logger writing into this logger's core.
+//
+// See SlogHandler for how records are mapped.
+//
+// Example:
+//
+//	sl := logger.Get().Slog()
+//	sl.Info("user created", slog.String("user_id", id))
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.SlogHandler())
+}
+
+// SlogHandler returns a slog.Handler writing into the global logger.
+//
+// This function panics if the logger has not been initialized.
+func SlogHandler() slog.Handler {
+	return Get().SlogHandler()
+}
+
+// Slog returns a *slog.Logger writing into the global logger.
+//
+// This function panics if the logger has not been initialized. To route
+// slog.Default through the global logger, initialize it with WithSlogDefault.
+func Slog() *slog.Logger {
+	return Get().Slog()
+}
+
+// Enabled reports whether the core accepts records at level.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(zapLevelFromSlog(level))
+}
+
+// Handle writes the record.
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      zapLevelFromSlog(record.Level),
+		Time:       record.Time,
+		LoggerName: h.log.Logger.Name(),
+		Message:    record.Message,
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+	}
+
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	var attrs []zap.Field
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = appendSlogAttr(attrs, attr)
+		return true
+	})
+
+	fields := make([]zap.Field, 0, len(h.pending)+len(h.groups)+len(attrs))
+	fields = append(fields, h.pending...)
+	if len(attrs) > 0 {
+		fields = append(append(fields, h.namespaces()...), attrs...)
+	}
+
+	ce.Write(h.log.contextFields(ctx, fields)...)
+	return nil
+}
+
+// WithAttrs returns a handler whose records include attrs.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []zap.Field
+	for _, attr := range attrs {
+		fields = appendSlogAttr(fields, attr)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	if len(h.pending) == 0 && len(h.groups) == 0 {
+		return &slogHandler{log: h.log, core: h.core.With(fields)}
+	}
+
+	pending := make([]zap.Field, 0, len(h.pending)+len(h.groups)+len(fields))
+	pending = append(pending, h.pending...)
+	pending = append(pending, h.namespaces()...)
+	return &slogHandler{
+		log:     h.log,
+		core:    h.core,
+		pending: append(pending, fields...),
+	}
+}
+
+// WithGroup returns a handler nesting subsequent attributes under name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	return &slogHandler{
+		log:     h.log,
+		core:    h.core,
+		pending: h.pending,
+		groups:  append(groups, name),
+	}
+}
+
+// namespaces returns the fields opening the groups without pending attributes.
+func (h *slogHandler) namespaces() []zap.Field {
+	fields := make([]zap.Field, 0, len(h.groups))
+	for _, group := range h.groups {
+		fields = append(fields, zap.Namespace(group))
+	}
+	return fields
+}
+
+// zapLevelFromSlog maps a slog level to the closest zap level at or below it.
+func zapLevelFromSlog(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// appendSlogAttr appends the zap fields for attr to fields. Empty attributes
+// and empty groups are skipped; groups without a key are inlined.
+func appendSlogAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	value := attr.Value
+	switch value.Kind() {
+	case slog.KindBool:
+		return append(fields, zap.Bool(attr.Key, value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(attr.Key, value.Duration()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(attr.Key, value.Float64()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(attr.Key, value.Int64()))
+	case slog.KindString:
+		return append(fields, zap.String(attr.Key, value.String()))
+	case slog.KindTime:
+		return append(fields, zap.Time(attr.Key, value.Time()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(attr.Key, value.Uint64()))
+	case slog.KindGroup:
+		attrs := value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if attr.Key == "" {
+			for _, a := range attrs {
+				fields = appendSlogAttr(fields, a)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(attr.Key, slogGroup(attrs)))
+	default:
+		return append(fields, zap.Any(attr.Key, value.Any()))
+	}
+}
+
+// slogGroup marshals the attributes of a slog group as a zap object.
+type slogGroup []slog.Attr
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, attr := range g {
+		for _, f := range appendSlogAttr(nil, attr) {
+			f.AddTo(enc)
+		}
+	}
+	return nil
+}
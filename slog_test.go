@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestZapLevelFromSlog tests the mapping of slog levels to zap levels.
+func TestZapLevelFromSlog(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  zapcore.Level
+	}{
+		{level: slog.LevelDebug - 4, want: zapcore.DebugLevel},
+		{level: slog.LevelDebug, want: zapcore.DebugLevel},
+		{level: slog.LevelInfo, want: zapcore.InfoLevel},
+		{level: slog.LevelInfo + 2, want: zapcore.InfoLevel},
+		{level: slog.LevelWarn, want: zapcore.WarnLevel},
+		{level: slog.LevelError, want: zapcore.ErrorLevel},
+		{level: slog.LevelError + 4, want: zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			if got := zapLevelFromSlog(tt.level); got != tt.want {
+				t.Errorf("zapLevelFromSlog(%v) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLogger_Slog tests that attributes, groups and logger fields are preserved.
+func TestLogger_Slog(t *testing.T) {
+	log, logs := newObservedLogger()
+	log = log.With(zap.String("service", "slog-service"))
+	sl := log.Slog()
+
+	sl.Info("plain", "count", 3, slog.Duration("took", time.Second))
+	sl.WithGroup("http").With("method", "GET").Info("grouped", "status", 200)
+	sl.WithGroup("empty").Info("no attrs")
+	sl.Info("nested", slog.Group("user", slog.String("id", "u-1")), slog.Group("", slog.String("inline", "yes")))
+	sl.Warn("failed", "err", errors.New("boom"))
+
+	entries := logs.All()
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+
+	plain := entries[0].ContextMap()
+	if plain["service"] != "slog-service" || plain["count"] != int64(3) || plain["took"] != time.Second {
+		t.Errorf("unexpected fields: %v", plain)
+	}
+	if !strings.HasSuffix(entries[0].Caller.File, "slog_test.go") {
+		t.Errorf("expected caller in slog_test.go, got %s", entries[0].Caller.File)
+	}
+
+	grouped := entries[1].ContextMap()
+	http, ok := grouped["http"].(map[string]any)
+	if !ok || http["method"] != "GET" || http["status"] != int64(200) {
+		t.Errorf("expected attributes nested under http, got %v", grouped)
+	}
+
+	if _, ok := entries[2].ContextMap()["empty"]; ok {
+		t.Errorf("expected empty group to be omitted, got %v", entries[2].ContextMap())
+	}
+
+	nested := entries[3].ContextMap()
+	user, ok := nested["user"].(map[string]any)
+	if !ok || user["id"] != "u-1" || nested["inline"] != "yes" {
+		t.Errorf("unexpected nested fields: %v", nested)
+	}
+
+	if entries[4].Level != zapcore.WarnLevel || entries[4].ContextMap()["err"] != "boom" {
+		t.Errorf("unexpected error entry: %v %v", entries[4].Level, entries[4].ContextMap())
+	}
+}
+
+// TestLogger_SlogLevel tests that the handler follows the dynamic level.
+func TestLogger_SlogLevel(t *testing.T) {
+	log := newTestLevelLogger(t)
+	handler := log.Named("db").SlogHandler()
+
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected DEBUG to be disabled at INFO")
+	}
+	if err := log.Named("db").SetLevel(LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected DEBUG to be enabled after SetLevel")
+	}
+}
+
+// TestLogger_SlogContext tests that context fields and span context are added.
+func TestLogger_SlogContext(t *testing.T) {
+	ctx, end := startTestSpan(t)
+	defer end()
+	ctx = ContextWithFields(ctx, zap.String("request_id", "abc"))
+
+	log, logs := newObservedLogger()
+	log.Slog().InfoContext(ctx, "traced")
+
+	fields := logs.All()[0].ContextMap()
+	if fields["request_id"] != "abc" || fields["trace_id"] == nil {
+		t.Errorf("expected context and trace fields, got %v", fields)
+	}
+}
+
+// TestLogger_SlogContextGroups tests that context fields and span context
+// stay at the top level of grouped handlers.
+func TestLogger_SlogContextGroups(t *testing.T) {
+	ctx, end := startTestSpan(t)
+	defer end()
+	ctx = ContextWithFields(ctx, zap.String("request_id", "abc"))
+
+	log, logs := newObservedLogger()
+	sl := log.Slog().With("service_version", "1.2").WithGroup("http").With("method", "GET").WithGroup("response")
+	sl.InfoContext(ctx, "served", "status", 200)
+	sl.InfoContext(ctx, "no attributes")
+
+	served := logs.All()[0].ContextMap()
+	if served["request_id"] != "abc" || served["trace_id"] == nil || served["service_version"] != "1.2" {
+		t.Errorf("expected context, trace and handler fields at the top level, got %v", served)
+	}
+	http, _ := served["http"].(map[string]any)
+	if http["method"] != "GET" || http["request_id"] != nil || http["trace_id"] != nil {
+		t.Errorf("expected only the handler attributes in the group, got %v", http)
+	}
+	if response, _ := http["response"].(map[string]any); response["status"] != int64(200) {
+		t.Errorf("expected record attributes in the nested group, got %v", http["response"])
+	}
+
+	bare := logs.All()[1].ContextMap()
+	if http, _ := bare["http"].(map[string]any); http["method"] != "GET" || http["response"] != nil {
+		t.Errorf("expected empty groups to be omitted, got %v", bare)
+	}
+}
+
+// TestInitGlobal_WithSlogDefault tests that slog.Default is replaced.
+func TestInitGlobal_WithSlogDefault(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	if err := InitWithDefaults(WithSlogDefault()); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+
+	handler, ok := slog.Default().Handler().(*slogHandler)
+	if !ok || handler.log != Get() {
+		t.Errorf("expected slog default to use the global logger, got %T", slog.Default().Handler())
+	}
+}
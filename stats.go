@@ -0,0 +1,56 @@
+package logger
+
+import "sync/atomic"
+
+// counters tracks entries discarded by a logger's pipeline. It is shared by
+// every logger derived from the same root.
+type counters struct {
+	asyncDropped    atomic.Uint64
+	sampledDropped  atomic.Uint64
+	dedupSuppressed atomic.Uint64
+	rateLimited     atomic.Uint64
+}
+
+// Stats reports entries discarded by the logging pipeline since the logger
+// was built.
+type Stats struct {
+	// AsyncDropped is the number of entries dropped because an async buffer
+	// was full (see AsyncConfig.Overflow).
+	AsyncDropped uint64
+
+	// SampledDropped is the number of entries dropped by sampling (see
+	// LoggerConfig.Sampling).
+	SampledDropped uint64
+
+	// DedupSuppressed is the number of entries suppressed as repeats (see
+	// LoggerConfig.Dedup). They are reported by summary entries.
+	DedupSuppressed uint64
+
+	// RateLimited is the number of entries dropped by rate limiting (see
+	// LoggerConfig.RateLimit).
+	RateLimited uint64
+}
+
+// Stats returns the counters of this logger's pipeline. They are shared by
+// every logger derived from the same root. Loggers not built by this package
+// report zero counters.
+//
+// Example:
+//
+//	stats := log.Stats()
+//	if dropped := stats.AsyncDropped; dropped > 0 {
+//	    metrics.Gauge("log_dropped", dropped)
+//	}
+//	metrics.Gauge("log_sampled_dropped", stats.SampledDropped)
+func (l *Logger) Stats() Stats {
+	gen := l.current()
+	if gen == nil {
+		return Stats{}
+	}
+	return Stats{
+		AsyncDropped:    gen.counters.asyncDropped.Load(),
+		SampledDropped:  gen.counters.sampledDropped.Load(),
+		DedupSuppressed: gen.counters.dedupSuppressed.Load(),
+		RateLimited:     gen.counters.rateLimited.Load(),
+	}
+}
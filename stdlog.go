@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"log"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stdLogCallerSkip skips the frames between a log.Printf call site and the
+// zap logger: the stdLogWriter, log.(*Logger).output and the log function
+// itself, so that the reported caller is the code that called the log
+// package.
+const stdLogCallerSkip = 3
+
+// RedirectStdLog redirects the output of the standard library log package
+// into the global logger at the given level.
+//
+// See (*Logger).RedirectStdLog for details.
+//
+// This function panics if the logger has not been initialized.
+func RedirectStdLog(level LogLevel) (func(), error) {
+	return Get().RedirectStdLog(level)
+}
+
+// RedirectStdLog redirects the output of the standard library log package
+// (log.Printf, log.Println, log.Default and so on) into this logger at the
+// given level, tagged with source="stdlib".
+//
+// The standard prefix and date, time and file headers are stripped from the
+// message, so entries carry only the text passed to the log package. The
+// caller of the entry is the code that called the log package. The returned
+// function restores the previous output, prefix and flags of the log package.
+//
+// Example:
+//
+//	undo, err := logger.Get().RedirectStdLog(logger.LogLevelWarn)
+//	if err != nil {
+//	    return err
+//	}
+//	defer undo()
+//
+//	log.Printf("legacy warning") // {"level":"warn",...,"message":"legacy warning","source":"stdlib"}
+func (l *Logger) RedirectStdLog(level LogLevel) (func(), error) {
+	zapLevel, err := toZapLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return l.redirectStdLog(zapLevel), nil
+}
+
+// redirectStdLog installs a stdLogWriter at level and returns the undo func.
+func (l *Logger) redirectStdLog(level zapcore.Level) func() {
+	prevOutput, prevPrefix, prevFlags := log.Writer(), log.Prefix(), log.Flags()
+
+	log.SetOutput(&stdLogWriter{
+		log: l.Logger.WithOptions(zap.AddCallerSkip(stdLogCallerSkip)).
+			With(zap.String("source", "stdlib")),
+		level: level,
+	})
+
+	return func() {
+		log.SetOutput(prevOutput)
+		log.SetPrefix(prevPrefix)
+		log.SetFlags(prevFlags)
+	}
+}
+
+// stdLogWriter is the output of the standard library log package while it is
+// redirected. Each Write receives exactly one formatted log line.
+type stdLogWriter struct {
+	log   *zap.Logger
+	level zapcore.Level
+}
+
+// Write logs the message contained in p.
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	if ce := w.log.Check(w.level, stripStdLogHeader(string(p), log.Prefix(), log.Flags())); ce != nil {
+		ce.Write()
+	}
+	return len(p), nil
+}
+
+// stripStdLogHeader removes the prefix and the headers selected by flags
+// (as formatted by the log package) and the trailing newline from line.
+func stripStdLogHeader(line, prefix string, flags int) string {
+	line = strings.TrimSuffix(line, "\n")
+	if flags&log.Lmsgprefix == 0 {
+		line = strings.TrimPrefix(line, prefix)
+	}
+
+	// "2009/01/23 "
+	if flags&log.Ldate != 0 {
+		line = skipField(line, len("2009/01/23"))
+	}
+	// "01:23:23 " or "01:23:23.123123 "
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		n := len("01:23:23")
+		if flags&log.Lmicroseconds != 0 {
+			n = len("01:23:23.123123")
+		}
+		line = skipField(line, n)
+	}
+	// "file.go:23: "
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if i := strings.Index(line, ": "); i >= 0 {
+			line = line[i+2:]
+		}
+	}
+
+	if flags&log.Lmsgprefix != 0 {
+		line = strings.TrimPrefix(line, prefix)
+	}
+	return line
+}
+
+// skipField removes a header field of n bytes and its trailing space from
+// line. line is returned unchanged if it does not start with such a field.
+func skipField(line string, n int) string {
+	if len(line) > n && line[n] == ' ' {
+		return line[n+1:]
+	}
+	return line
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestStripStdLogHeader tests removal of the prefix and flag headers.
+func TestStripStdLogHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		prefix string
+		flags  int
+		want   string
+	}{
+		{name: "no flags", line: "hello\n", want: "hello"},
+		{name: "std flags", line: "2009/01/23 01:23:23 hello\n", flags: log.LstdFlags, want: "hello"},
+		{name: "microseconds", line: "01:23:23.123123 hello\n", flags: log.Lmicroseconds, want: "hello"},
+		{name: "short file", line: "2009/01/23 main.go:23: hello: world\n", flags: log.Ldate | log.Lshortfile, want: "hello: world"},
+		{name: "prefix", line: "[app] 2009/01/23 hello\n", prefix: "[app] ", flags: log.Ldate, want: "hello"},
+		{name: "message prefix", line: "2009/01/23 [app] hello\n", prefix: "[app] ", flags: log.Ldate | log.Lmsgprefix, want: "hello"},
+		{name: "multi-line", line: "first\nsecond\n", want: "first\nsecond"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripStdLogHeader(tt.line, tt.prefix, tt.flags); got != tt.want {
+				t.Errorf("stripStdLogHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLogger_RedirectStdLog tests that the log package writes into the logger.
+func TestLogger_RedirectStdLog(t *testing.T) {
+	log.SetPrefix("[legacy] ")
+	defer log.SetPrefix("")
+	flags := log.Flags()
+
+	l, logs := newObservedLogger()
+	undo, err := l.RedirectStdLog(LogLevelWarn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	log.Printf("disk %s", "full")
+	log.SetPrefix("[changed] ")
+	log.SetFlags(log.Lshortfile)
+	undo()
+	log.SetOutput(&strings.Builder{})
+	log.Print("after undo")
+	log.SetOutput(os.Stderr)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Message != "disk full" || entry.Level != zapcore.WarnLevel {
+		t.Errorf("unexpected entry: %q at %v", entry.Message, entry.Level)
+	}
+	if entry.ContextMap()["source"] != "stdlib" {
+		t.Errorf("expected source=stdlib, got %v", entry.ContextMap())
+	}
+	if !strings.HasSuffix(entry.Caller.File, "stdlog_test.go") {
+		t.Errorf("expected caller in stdlog_test.go, got %s", entry.Caller.File)
+	}
+	if log.Prefix() != "[legacy] " || log.Flags() != flags {
+		t.Errorf("expected undo to restore prefix and flags, got %q %d", log.Prefix(), log.Flags())
+	}
+}
+
+// TestLogger_RedirectStdLog_InvalidLevel tests rejection of invalid levels.
+func TestLogger_RedirectStdLog_InvalidLevel(t *testing.T) {
+	l, _ := newObservedLogger()
+	if _, err := l.RedirectStdLog("TRACE"); !errors.Is(err, ErrInvalidLogLevel) {
+		t.Errorf("expected ErrInvalidLogLevel, got %v", err)
+	}
+}
+
+// TestInitGlobal_WithStdLogRedirect tests the redirect installed at initialization.
+func TestInitGlobal_WithStdLogRedirect(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+	defer log.SetOutput(os.Stderr)
+
+	if err := InitWithDefaults(WithStdLogRedirect("TRACE")); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	if _, err := TryGet(); !errors.Is(err, ErrNotInitialized) {
+		t.Fatalf("expected logger to stay uninitialized, got %v", err)
+	}
+
+	if err := InitWithDefaults(WithStdLogRedirect(LogLevelInfo)); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	w, ok := log.Writer().(*stdLogWriter)
+	if !ok || w.level != zapcore.InfoLevel {
+		t.Errorf("expected log output to be redirected at INFO, got %T", log.Writer())
+	}
+}
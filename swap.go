@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// generation is what building a configuration produces: the pipeline below
+// the level filter and the outputs it writes to. A root logger writes to one
+// generation at a time, replaced by rebuild.
+type generation struct {
+	core    zapcore.Core
+	closers []io.Closer
+
+	// counters tracks entries discarded by the pipeline. It is carried over
+	// from one generation to the next.
+	counters *counters
+
+	// trace names the span context fields added by the Ctx functions.
+	trace config.TraceConfig
+
+	// stacktrace is the level from which entries carry a stack trace.
+	stacktrace zapcore.Level
+
+	// cfg is the configuration the generation was built from.
+	cfg *config.LoggerConfig
+}
+
+// buildGeneration builds the pipeline of cfg, counting discarded entries in
+// counters.
+func buildGeneration(cfg config.LoggerConfig, counters *counters) (*generation, error) {
+	redact, err := buildRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create outputs
+	outputs, closers, err := buildOutputs(cfg, counters)
+	if err != nil {
+		return nil, err
+	}
+	sampling := cfg.Sampling
+	if preset, _ := cfg.Environment.Preset(); preset.Sampling && !sampling.Disabled {
+		sampling.Enabled = true
+	}
+	if sampling.Enabled {
+		outputs = newSamplingCore(outputs, sampling, &counters.sampledDropped)
+	}
+	// Deduplication wraps the rate limiter, so that suppressed repeats do
+	// not use up the tokens of their call site.
+	if cfg.RateLimit.Enabled {
+		outputs = newRateLimitCore(outputs, cfg.RateLimit, &counters.rateLimited)
+	}
+	if cfg.Dedup.Enabled {
+		outputs = newDedupCore(outputs, cfg.Dedup, &counters.dedupSuppressed)
+	}
+	if redact != nil {
+		outputs = newRedactCore(outputs, redact)
+	}
+
+	return &generation{
+		core:       outputs.With([]zapcore.Field{zap.String("service", cfg.ServiceName)}),
+		closers:    closers,
+		counters:   counters,
+		trace:      cfg.Trace.WithDefaults(),
+		stacktrace: stacktraceLevel(cfg.Environment),
+		cfg:        &cfg,
+	}, nil
+}
+
+// close flushes the pipeline and releases its outputs.
+func (g *generation) close() error {
+	var err error
+	if serr := g.core.Sync(); serr != nil && !isIgnorableSyncError(serr) {
+		err = serr
+	}
+	for _, c := range g.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// swapCore is the core below the level filter of the loggers built by this
+// package. It writes to the current generation of their root, so that
+// loggers derived with With or Named follow a rebuild. Fields added with
+// With are added again to the core of each new generation on first use.
+type swapCore struct {
+	gen    *atomic.Pointer[generation]
+	fields []zapcore.Field
+
+	// derived caches the current generation's core with fields added.
+	derived atomic.Pointer[derivedCore]
+}
+
+// derivedCore is the core of gen with the fields of a swapCore added.
+type derivedCore struct {
+	gen  *generation
+	core zapcore.Core
+}
+
+// current returns the core of the current generation with c's fields.
+func (c *swapCore) current() zapcore.Core {
+	gen := c.gen.Load()
+	if len(c.fields) == 0 {
+		return gen.core
+	}
+	if d := c.derived.Load(); d != nil && d.gen == gen {
+		return d.core
+	}
+	core := gen.core.With(c.fields)
+	c.derived.Store(&derivedCore{gen: gen, core: core})
+	return core
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *swapCore) Enabled(level zapcore.Level) bool {
+	return c.current().Enabled(level)
+}
+
+// With implements zapcore.Core.
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	return &swapCore{gen: c.gen, fields: append(merged, fields...)}
+}
+
+// Check implements zapcore.Core.
+func (c *swapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.current().Check(ent, ce)
+}
+
+// Write implements zapcore.Core.
+func (c *swapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.current().Write(ent, fields)
+}
+
+// Sync implements zapcore.Core.
+func (c *swapCore) Sync() error {
+	return c.current().Sync()
+}
+
+// stacktraceEnabler enables stack traces from the level of the current
+// generation of a root logger.
+type stacktraceEnabler struct {
+	gen *atomic.Pointer[generation]
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (e stacktraceEnabler) Enabled(level zapcore.Level) bool {
+	return level >= e.gen.Load().stacktrace
+}
+
+// rebuild applies cfg to l and every logger sharing its root, in place: the
+// levels are replaced and entries are written to a new pipeline built from
+// cfg. l must have been built by this package. It returns the previous
+// generation, which the caller closes once writes already under way are
+// done. Runtime level changes are discarded.
+func (l *Logger) rebuild(cfg config.LoggerConfig) (*generation, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	root, err := toZapLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := toZapLevels(cfg.Levels)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := buildGeneration(cfg, l.gen.Load().counters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logger: %w", err)
+	}
+
+	l.levels.replace(root, overrides)
+	previous := l.gen.Swap(gen)
+	if err := previous.core.Sync(); err != nil && !isIgnorableSyncError(err) {
+		return previous, fmt.Errorf("%w: %v", ErrSyncFailed, err)
+	}
+	return previous, nil
+}
+
+// current returns the generation l writes to, or nil for loggers not built
+// by this package.
+func (l *Logger) current() *generation {
+	if l.gen == nil {
+		return nil
+	}
+	return l.gen.Load()
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+)
+
+// tokenPrefix starts the tokens produced by Tokenize.
+const tokenPrefix = "tok:"
+
+// Token returns a field whose value is replaced with a keyed token before
+// entries are encoded, so that logs can correlate the activity of a user
+// without holding the user ID. The key is set by
+// LoggerConfig.Tokenization; the same value logged by services sharing
+// the key produces the same token.
+//
+// Loggers without a tokenization key write "[REDACTED]" instead, never the
+// value itself.
+//
+// Example:
+//
+//	log.Info("order placed", logger.Token("user_id", userID))
+//	// {"message":"order placed","user_id":"tok:2024-06:9f2c...",...}
+func Token(key, value string) zap.Field {
+	return zap.Stringer(key, tokenValue(value))
+}
+
+// tokenValue is the value of a field built with Token.
+type tokenValue string
+
+// String hides the value when the field is encoded without tokenization.
+func (tokenValue) String() string {
+	return redactedValue
+}
+
+// Tokenize returns the token of value under the key with the given ID, as
+// written in entries: "tok:<key ID>:<hex HMAC-SHA256 digest prefix>".
+//
+// Tokens cannot be reversed. Holders of the key re-identify a token by
+// tokenizing candidate values, such as the user IDs of a database, and
+// comparing the results (see cmd/detokenize).
+func Tokenize(key []byte, keyID, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return tokenPrefix + keyID + ":" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// TokenKeyID returns the ID of the key of token, and whether token has the
+// form of the tokens produced by Tokenize.
+func TokenKeyID(token string) (string, bool) {
+	rest, ok := strings.CutPrefix(token, tokenPrefix)
+	if !ok {
+		return "", false
+	}
+	keyID, digest, ok := strings.Cut(rest, ":")
+	if !ok || keyID == "" || len(digest) != 32 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(digest); err != nil {
+		return "", false
+	}
+	return keyID, true
+}
+
+// tokenizer produces the tokens of a logger.
+type tokenizer struct {
+	key   []byte
+	keyID string
+}
+
+// newTokenizer loads the key of cfg. It returns nil if no key is configured.
+func newTokenizer(cfg config.TokenizationConfig) (*tokenizer, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	key, err := cfg.LoadKey()
+	if err != nil {
+		return nil, err
+	}
+	return &tokenizer{key: key, keyID: cfg.KeyID}, nil
+}
+
+// token returns the token of value.
+func (t *tokenizer) token(value string) string {
+	return Tokenize(t.key, t.keyID, value)
+}
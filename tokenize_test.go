@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// testTokenKey is a tokenization key used by tests.
+const testTokenKey = "0123456789abcdef0123456789abcdef"
+
+// TestTokenize tests that tokens are deterministic per key and carry the key ID.
+func TestTokenize(t *testing.T) {
+	token := Tokenize([]byte(testTokenKey), "k1", "user-42")
+
+	if !strings.HasPrefix(token, "tok:k1:") || len(token) != len("tok:k1:")+32 {
+		t.Errorf("unexpected token %q", token)
+	}
+	if again := Tokenize([]byte(testTokenKey), "k1", "user-42"); again != token {
+		t.Errorf("expected the same token for the same value, got %q and %q", token, again)
+	}
+	if other := Tokenize([]byte(testTokenKey), "k1", "user-43"); other == token {
+		t.Error("expected different tokens for different values")
+	}
+	rotated := Tokenize([]byte("fedcba9876543210fedcba9876543210"), "k2", "user-42")
+	if strings.TrimPrefix(rotated, "tok:k2:") == strings.TrimPrefix(token, "tok:k1:") {
+		t.Error("expected different digests for different keys")
+	}
+}
+
+// TestTokenKeyID tests parsing the key ID of tokens.
+func TestTokenKeyID(t *testing.T) {
+	tests := []struct {
+		token  string
+		want   string
+		wantOK bool
+	}{
+		{token: Tokenize([]byte(testTokenKey), "2024-06", "user-42"), want: "2024-06", wantOK: true},
+		{token: "user-42"},
+		{token: "tok:k1"},
+		{token: "tok::0123456789abcdef0123456789abcdef"},
+		{token: "tok:k1:0123"},
+		{token: "tok:k1:0123456789abcdef0123456789abcdeg"},
+	}
+
+	for _, tt := range tests {
+		got, ok := TokenKeyID(tt.token)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("TokenKeyID(%q) = %q, %v, want %q, %v", tt.token, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// TestToken tests that Token fields are tokenized, or hidden without a key.
+func TestToken(t *testing.T) {
+	want := Tokenize([]byte(testTokenKey), "k1", "user-42")
+
+	tests := []struct {
+		name   string
+		tokens *tokenizer
+		want   string
+	}{
+		{name: "with key", tokens: &tokenizer{key: []byte(testTokenKey), keyID: "k1"}, want: want},
+		{name: "without key", want: "[REDACTED]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newRedactor(config.RedactionConfig{}, tt.tokens)
+			if err != nil {
+				t.Fatalf("failed to build redactor: %v", err)
+			}
+			observed, logs := observer.New(zapcore.DebugLevel)
+			log := zap.New(newRedactCore(observed, r))
+
+			log.With(Token("user_id", "user-42")).Info("login")
+			log.Info("order placed", Token("user_id", "user-42"))
+
+			for _, entry := range logs.All() {
+				if got := entry.ContextMap()["user_id"]; got != tt.want {
+					t.Errorf("%s: expected %v, got %v", entry.Message, tt.want, got)
+				}
+			}
+		})
+	}
+
+	t.Run("without redaction core", func(t *testing.T) {
+		observed, logs := observer.New(zapcore.DebugLevel)
+		zap.New(observed).Info("login", Token("user_id", "user-42"))
+		if got := logs.All()[0].ContextMap()["user_id"]; got != "[REDACTED]" {
+			t.Errorf("expected hidden value, got %v", got)
+		}
+	})
+}
+
+// TestRedactCore_Tokenize tests redaction rules with the tokenize action.
+func TestRedactCore_Tokenize(t *testing.T) {
+	tokens := &tokenizer{key: []byte(testTokenKey), keyID: "k1"}
+	r, err := newRedactor(config.RedactionConfig{
+		Enabled: true,
+		Rules:   []config.RedactionRule{{Keys: []string{"user_id"}, ValuePatterns: []string{config.PatternEmail}, Action: config.RedactTokenize}},
+	}, tokens)
+	if err != nil {
+		t.Fatalf("failed to build redactor: %v", err)
+	}
+	observed, logs := observer.New(zapcore.DebugLevel)
+	zap.New(newRedactCore(observed, r)).Info("mail to jane@example.com",
+		zap.Int("user_id", 42),
+		zap.Any("order", map[string]any{"user_id": "42"}),
+	)
+
+	entry := logs.All()[0]
+	if want := "mail to " + tokens.token("jane@example.com"); entry.Message != want {
+		t.Errorf("expected message %q, got %q", want, entry.Message)
+	}
+	fields := entry.ContextMap()
+	if got := fields["user_id"]; got != tokens.token("42") {
+		t.Errorf("expected tokenized user_id, got %v", got)
+	}
+	if got := fields["order"].(map[string]any)["user_id"]; got != fields["user_id"] {
+		t.Errorf("expected the same token in nested fields, got %v", got)
+	}
+
+	if _, err := newRedactor(config.RedactionConfig{
+		Enabled: true,
+		Rules:   []config.RedactionRule{{Keys: []string{"user_id"}, Action: config.RedactTokenize}},
+	}, nil); err == nil {
+		t.Error("expected error for the tokenize action without key")
+	}
+}
+
+// TestBuildLogger_Tokenization tests that the tokenization key from the
+// configuration is used by Token fields.
+func TestBuildLogger_Tokenization(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "token-key")
+	if err := os.WriteFile(keyFile, []byte(testTokenKey+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	path := filepath.Join(dir, "app.log")
+	log, err := buildLogger(config.LoggerConfig{
+		Level:        config.LogLevelInfo,
+		Environment:  config.EnvProduction,
+		ServiceName:  "test-service",
+		Outputs:      []config.OutputConfig{{Type: config.OutputFile, File: config.FileConfig{Path: path}}},
+		Tokenization: config.TokenizationConfig{KeyID: "k1", KeyFile: keyFile},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+
+	log.Info("login", Token("user_id", "user-42"))
+	if err := log.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := readLines(t, path)
+	want := `"user_id":"` + Tokenize([]byte(testTokenKey), "k1", "user-42") + `"`
+	if len(lines) != 1 || !strings.Contains(lines[0], want) {
+		t.Errorf("expected %s in %v", want, lines)
+	}
+}
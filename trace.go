@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/gath-stack/gologger/config"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// traceFields returns the trace ID, span ID and trace flags of the
+// OpenTelemetry span carried by ctx, named according to the logger's
+// TraceConfig. It returns nil if ctx carries no valid span context.
+func (l *Logger) traceFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	// Loggers not built by this package use the default names.
+	keys := config.TraceConfig{}.WithDefaults()
+	if gen := l.current(); gen != nil {
+		keys = gen.trace
+	}
+	return []zap.Field{
+		zap.String(keys.TraceIDKey, sc.TraceID().String()),
+		zap.String(keys.SpanIDKey, sc.SpanID().String()),
+		zap.String(keys.TraceFlagsKey, sc.TraceFlags().String()),
+	}
+}
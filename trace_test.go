@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gath-stack/gologger/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// startTestSpan starts a sampled span with an in-memory tracer.
+func startTestSpan(t *testing.T) (context.Context, func()) {
+	t.Helper()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(tracetest.NewInMemoryExporter()))
+	ctx, span := provider.Tracer("gologger-test").Start(context.Background(), "operation")
+	return ctx, func() {
+		span.End()
+		_ = provider.Shutdown(context.Background())
+	}
+}
+
+// TestCtxFunctions_Trace tests that the active span is added to entries.
+func TestCtxFunctions_Trace(t *testing.T) {
+	ctx, end := startTestSpan(t)
+	defer end()
+
+	tests := []struct {
+		name                        string
+		trace                       config.TraceConfig
+		traceKey, spanKey, flagsKey string
+	}{
+		{
+			name:     "default names",
+			traceKey: "trace_id", spanKey: "span_id", flagsKey: "trace_flags",
+		},
+		{
+			name:     "custom names",
+			trace:    config.TraceConfig{TraceIDKey: "dd.trace_id", SpanIDKey: "dd.span_id", TraceFlagsKey: "dd.flags"},
+			traceKey: "dd.trace_id", spanKey: "dd.span_id", flagsKey: "dd.flags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, logs := newObservedLogger()
+			if tt.trace != (config.TraceConfig{}) {
+				log.gen = &atomic.Pointer[generation]{}
+				log.gen.Store(&generation{trace: tt.trace.WithDefaults()})
+			}
+
+			InfoCtx(NewContext(ctx, log), "traced", zap.String("k", "v"))
+
+			fields := logs.All()[0].ContextMap()
+			sc := trace.SpanContextFromContext(ctx)
+			if fields[tt.traceKey] != sc.TraceID().String() {
+				t.Errorf("expected %s %s, got %v", tt.traceKey, sc.TraceID(), fields[tt.traceKey])
+			}
+			if fields[tt.spanKey] != sc.SpanID().String() {
+				t.Errorf("expected %s %s, got %v", tt.spanKey, sc.SpanID(), fields[tt.spanKey])
+			}
+			if fields[tt.flagsKey] != "01" {
+				t.Errorf("expected %s 01, got %v", tt.flagsKey, fields[tt.flagsKey])
+			}
+			if fields["k"] != "v" {
+				t.Errorf("expected call fields to be kept, got %v", fields)
+			}
+		})
+	}
+}
+
+// TestCtxFunctions_NoSpan tests that no trace fields are added without a span.
+func TestCtxFunctions_NoSpan(t *testing.T) {
+	log, logs := newObservedLogger()
+
+	InfoCtx(NewContext(context.Background(), log), "untraced")
+
+	fields := logs.All()[0].ContextMap()
+	if _, ok := fields["trace_id"]; ok {
+		t.Errorf("unexpected trace fields: %v", fields)
+	}
+}
+
+// TestBuildLogger_TraceConfig tests that configured names are used.
+func TestBuildLogger_TraceConfig(t *testing.T) {
+	log, err := buildLogger(config.LoggerConfig{
+		Level:       config.LogLevelInfo,
+		Environment: config.EnvProduction,
+		ServiceName: "trace-service",
+		Trace:       config.TraceConfig{TraceIDKey: "traceId"},
+	})
+	if err != nil {
+		t.Fatalf("failed to build logger: %v", err)
+	}
+	defer log.Close()
+
+	want := config.TraceConfig{TraceIDKey: "traceId", SpanIDKey: "span_id", TraceFlagsKey: "trace_flags"}
+	if got := log.current().trace; got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := log.Named("db").With(zap.Int("n", 1)).current().trace; got != want {
+		t.Errorf("expected derived loggers to keep trace names, got %+v", got)
+	}
+}
@@ -1,6 +1,6 @@
 package logger
 
-import "github.com/gath-stack/gologger/internal/config"
+import "github.com/gath-stack/gologger/config"
 
 // Re-export config types for convenience and backward compatibility.
 // This allows users to reference logger.LogLevel instead of config.LogLevel
@@ -26,6 +26,10 @@ type Environment = config.Environment
 const (
 	// EnvDevelopment represents the development environment.
 	EnvDevelopment = config.EnvDevelopment
+	// EnvTest represents automated test runs.
+	EnvTest = config.EnvTest
+	// EnvStaging represents the staging environment.
+	EnvStaging = config.EnvStaging
 	// EnvProduction represents the production environment.
 	EnvProduction = config.EnvProduction
 )
@@ -33,3 +37,146 @@ const (
 // LoggerConfig defines the configuration parameters for the logger.
 // This type is defined in the config package and re-exported here.
 type LoggerConfig = config.LoggerConfig
+
+// FileConfig defines a rotating log file output.
+// This type is defined in the config package and re-exported here.
+type FileConfig = config.FileConfig
+
+// OutputType identifies the destination of an output.
+type OutputType = config.OutputType
+
+const (
+	// OutputStdout writes to the standard output.
+	OutputStdout = config.OutputStdout
+	// OutputStderr writes to the standard error.
+	OutputStderr = config.OutputStderr
+	// OutputFile writes to a rotating file.
+	OutputFile = config.OutputFile
+	// OutputNetwork writes to a TCP or UDP endpoint, one entry per line.
+	OutputNetwork = config.OutputNetwork
+)
+
+// Encoding selects how an output serializes entries.
+type Encoding = config.Encoding
+
+const (
+	// EncodingJSON writes one JSON object per entry.
+	EncodingJSON = config.EncodingJSON
+	// EncodingConsole writes human-readable, tab-separated entries.
+	EncodingConsole = config.EncodingConsole
+)
+
+// OutputConfig defines a single log destination.
+// This type is defined in the config package and re-exported here.
+type OutputConfig = config.OutputConfig
+
+// AsyncConfig defines asynchronous, buffered writing to outputs.
+// This type is defined in the config package and re-exported here.
+type AsyncConfig = config.AsyncConfig
+
+// OverflowPolicy decides what happens to an entry when the async buffer is full.
+type OverflowPolicy = config.OverflowPolicy
+
+const (
+	// OverflowBlock waits for room in the buffer.
+	OverflowBlock = config.OverflowBlock
+	// OverflowDropNewest discards the entry being logged.
+	OverflowDropNewest = config.OverflowDropNewest
+	// OverflowDropOldest discards the oldest buffered entry.
+	OverflowDropOldest = config.OverflowDropOldest
+	// OverflowDropBelow discards entries below AsyncConfig.DropBelow.
+	OverflowDropBelow = config.OverflowDropBelow
+)
+
+// SamplingConfig defines the sampling of repeated entries, per level.
+// This type is defined in the config package and re-exported here.
+type SamplingConfig = config.SamplingConfig
+
+// SamplingPolicy limits repeated entries of a level.
+// This type is defined in the config package and re-exported here.
+type SamplingPolicy = config.SamplingPolicy
+
+// DedupConfig defines the deduplication of identical entries.
+// This type is defined in the config package and re-exported here.
+type DedupConfig = config.DedupConfig
+
+// RateLimitConfig defines token-bucket rate limiting of entries.
+// This type is defined in the config package and re-exported here.
+type RateLimitConfig = config.RateLimitConfig
+
+// RateLimit is a token bucket limiting entries.
+// This type is defined in the config package and re-exported here.
+type RateLimit = config.RateLimit
+
+// RateLimitKey selects what gets its own rate limit.
+type RateLimitKey = config.RateLimitKey
+
+const (
+	// RateLimitByCaller limits each call site separately.
+	RateLimitByCaller = config.RateLimitByCaller
+	// RateLimitByLogger limits each named logger separately.
+	RateLimitByLogger = config.RateLimitByLogger
+)
+
+// RedactionConfig defines the redaction of sensitive values.
+// This type is defined in the config package and re-exported here.
+type RedactionConfig = config.RedactionConfig
+
+// RedactionRule selects sensitive values and how they are rewritten.
+// This type is defined in the config package and re-exported here.
+type RedactionRule = config.RedactionRule
+
+// RedactAction decides how a sensitive value is rewritten.
+type RedactAction = config.RedactAction
+
+const (
+	// RedactReplace replaces the value with "[REDACTED]".
+	RedactReplace = config.RedactReplace
+	// RedactMask masks all but the last characters of the value.
+	RedactMask = config.RedactMask
+	// RedactHash replaces the value with a SHA-256 digest.
+	RedactHash = config.RedactHash
+	// RedactTokenize replaces the value with a keyed token.
+	RedactTokenize = config.RedactTokenize
+)
+
+// TokenizationConfig holds the key of the tokens replacing sensitive identifiers.
+// This type is defined in the config package and re-exported here.
+type TokenizationConfig = config.TokenizationConfig
+
+// TraceConfig names the fields carrying OpenTelemetry span context.
+// This type is defined in the config package and re-exported here.
+type TraceConfig = config.TraceConfig
+
+// OTLPConfig defines the export of entries as OpenTelemetry log records.
+// This type is defined in the config package and re-exported here.
+type OTLPConfig = config.OTLPConfig
+
+// OTLPProtocol is the transport used to export log records.
+type OTLPProtocol = config.OTLPProtocol
+
+const (
+	// OTLPProtocolHTTP sends protobuf-encoded records over HTTP.
+	OTLPProtocolHTTP = config.OTLPProtocolHTTP
+	// OTLPProtocolGRPC sends records over gRPC.
+	OTLPProtocolGRPC = config.OTLPProtocolGRPC
+)
+
+// ValidationError lists every missing or invalid configuration value
+// reported by InitFromEnv and InitFromFile.
+// This type is defined in the config package and re-exported here.
+type ValidationError = config.ValidationError
+
+// FieldError is the failure of a single configuration value.
+// This type is defined in the config package and re-exported here.
+type FieldError = config.FieldError
+
+// LoadOptions selects the environment variables and .env files read when
+// loading configuration. See WithLoadOptions.
+// This type is defined in the config package and re-exported here.
+type LoadOptions = config.LoadOptions
+
+// Preset holds the defaults the logger applies in an environment. Register
+// presets with config.RegisterEnvironment.
+// This type is defined in the config package and re-exported here.
+type Preset = config.Preset
@@ -0,0 +1,257 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+)
+
+// defaultWatchInterval is how often a Watcher polls its file by default.
+const defaultWatchInterval = 2 * time.Second
+
+// retireGracePeriod is how long the outputs replaced by a reload stay open
+// for writes already under way before they are closed.
+const retireGracePeriod = 30 * time.Second
+
+// Watcher reloads the configuration of the global logger when a
+// configuration file changes. Create one with WatchConfigFile.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	opts     []Option
+
+	// mu serializes reloads and guards the fields below.
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	// current is the configuration last applied to logger, the global logger
+	// at that time.
+	current *config.LoggerConfig
+	logger  *Logger
+	// retired is the generation replaced by the last rebuild, closed by
+	// retireTimer after grace, by the next rebuild, or by Close.
+	retired     *generation
+	retireTimer *time.Timer
+	grace       time.Duration
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// WatchConfigFile polls the configuration file at path every interval (2s if
+// zero) and applies its configuration to the global logger whenever the file
+// changes.
+//
+// Files ending in .yaml, .yml, .json or .toml are read as with InitFromFile,
+// environment variables overriding their values. Other files are read as env
+// files, their values taking precedence over the process environment. On
+// each change the configuration is validated:
+//   - If it is invalid, the global logger is left untouched and a
+//     "config rejected" warning with the validation error is logged.
+//   - If only the levels (LOG_LEVEL, LOG_LEVELS) changed, they are applied in
+//     place, so loggers already derived from the global logger follow them.
+//   - Otherwise the global logger is rebuilt in place: loggers already
+//     derived from it, with With or Named, write to the new outputs too.
+//     The previous outputs are closed after a grace period of 30 seconds,
+//     at the next rebuild, or when the watcher is closed, whichever comes
+//     first. A global logger not built by this package is replaced with
+//     Reconfigure instead, passing opts.
+//
+// A "config reloaded" entry is logged after each change is applied. Level
+// changes made at runtime with SetLevel are discarded by a reload.
+//
+// Example:
+//
+//	w, err := logger.WatchConfigFile(".env", 0)
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Close()
+func WatchConfigFile(path string, interval time.Duration, opts ...Option) (*Watcher, error) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+
+	w := &Watcher{
+		path:     path,
+		interval: interval,
+		opts:     opts,
+		grace:    retireGracePeriod,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if current, err := TryGet(); err == nil && current.gen != nil {
+		w.logger, w.current = current, current.current().cfg
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// run polls the file until the watcher is closed.
+func (w *Watcher) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if w.changed() {
+				_ = w.Reload()
+			}
+		}
+	}
+}
+
+// changed reports whether the file was modified since the last poll. A
+// missing file, e.g. while it is being replaced, is not a change.
+func (w *Watcher) changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	return true
+}
+
+// Reload reads the file and applies it immediately, as when it changes.
+//
+// It returns the error that caused the configuration to be rejected, if any.
+// This is useful to reload on a signal such as SIGHUP.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cfg, err := loadConfigFile(w.path, newInitOptions(w.opts).load)
+	if err == nil {
+		err = w.apply(cfg.Logger)
+	}
+	if err != nil {
+		if log, getErr := TryGet(); getErr == nil {
+			log.Warn("config rejected", zap.String("path", w.path), zap.Error(err))
+		}
+		return err
+	}
+
+	Get().Info("config reloaded", zap.String("path", w.path))
+	return nil
+}
+
+// apply applies cfg to the global logger, in place if it was built by this
+// package.
+func (w *Watcher) apply(cfg config.LoggerConfig) error {
+	current, err := TryGet()
+	if err != nil || current.gen == nil {
+		if _, err := Reconfigure(cfg, w.opts...); err != nil {
+			return err
+		}
+		w.logger, w.current = Get(), &cfg
+		return nil
+	}
+
+	if current == w.logger && w.current != nil && onlyLevelsDiffer(*w.current, cfg) {
+		root, err := toZapLevel(cfg.Level)
+		if err != nil {
+			return err
+		}
+		overrides, err := toZapLevels(cfg.Levels)
+		if err != nil {
+			return err
+		}
+		current.levels.replace(root, overrides)
+		w.current = &cfg
+		return nil
+	}
+
+	previous, err := current.rebuild(cfg)
+	if previous != nil {
+		w.retire(previous)
+	}
+	if err != nil && previous == nil {
+		return err
+	}
+	w.logger, w.current = current, &cfg
+	return nil
+}
+
+// retire closes the generation retired by the previous rebuild, if still
+// open, and schedules gen to be closed after the grace period. Callers must
+// hold w.mu.
+func (w *Watcher) retire(gen *generation) {
+	_ = w.closeRetired()
+	w.retired = gen
+	w.retireTimer = time.AfterFunc(w.grace, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.retired == gen {
+			_ = w.closeRetired()
+		}
+	})
+}
+
+// closeRetired flushes and closes the retired generation, if any. Callers
+// must hold w.mu.
+func (w *Watcher) closeRetired() error {
+	if w.retired == nil {
+		return nil
+	}
+	w.retireTimer.Stop()
+	err := w.retired.close()
+	w.retired, w.retireTimer = nil, nil
+	return err
+}
+
+// loadConfigFile loads the configuration file at path according to its
+// extension.
+func loadConfigFile(path string, opts config.LoadOptions) (config.Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json", ".toml":
+		return opts.LoadFile(path)
+	default:
+		return opts.LoadFromEnvFile(path)
+	}
+}
+
+// onlyLevelsDiffer reports whether a and b are equal except for their levels.
+func onlyLevelsDiffer(a, b config.LoggerConfig) bool {
+	a.Level, a.Levels = b.Level, b.Levels
+	return reflect.DeepEqual(a, b)
+}
+
+// Close stops watching the file, then flushes and closes the outputs
+// replaced by the last reload, if still open. The global logger itself is
+// not closed.
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closeRetired()
+}
@@ -0,0 +1,307 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gath-stack/gologger/config"
+	"go.uber.org/zap"
+)
+
+// writeEnvFile writes an env file with the given level and log file path.
+func writeEnvFile(t *testing.T, path, level, logFile string) {
+	t.Helper()
+	content := "LOG_LEVEL=" + level + "\nAPP_ENV=production\nAPP_NAME=watch-service\nLOG_OUTPUTS=file?path=" + logFile + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+}
+
+// initFromEnvFile initializes the global logger from the env file at path.
+func initFromEnvFile(t *testing.T, path string) {
+	t.Helper()
+	cfg, err := config.LoadFromEnvFile(path)
+	if err != nil {
+		t.Fatalf("failed to load env file: %v", err)
+	}
+	if err := InitGlobal(cfg.Logger); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+}
+
+// readLog returns the content of the log file at path.
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	return string(data)
+}
+
+// TestWatchConfigFile tests that file changes are picked up by polling.
+func TestWatchConfigFile(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	dir := t.TempDir()
+	envFile, logFile := filepath.Join(dir, ".env"), filepath.Join(dir, "app.log")
+	writeEnvFile(t, envFile, "INFO", logFile)
+	initFromEnvFile(t, envFile)
+	defer Get().Close()
+
+	w, err := WatchConfigFile(envFile, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer w.Close()
+
+	dbLog := Named("db")
+	writeEnvFile(t, envFile, "DEBUG", logFile)
+	// Make the change visible even on file systems with coarse timestamps.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(envFile, future, future); err != nil {
+		t.Fatalf("failed to touch env file: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for GetLevel() != LogLevelDebug {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the level to be reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if dbLog.GetLevel() != LogLevelDebug {
+		t.Error("expected derived loggers to follow an in-place level change")
+	}
+}
+
+// TestWatcher_Reload tests in-place level changes, rebuilds and rejections.
+func TestWatcher_Reload(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	firstLog, secondLog := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	writeEnvFile(t, envFile, "INFO", firstLog)
+	initFromEnvFile(t, envFile)
+
+	w, err := WatchConfigFile(envFile, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	first := Get()
+	writeEnvFile(t, envFile, "DEBUG", firstLog)
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if Get() != first || GetLevel() != LogLevelDebug {
+		t.Errorf("expected level change to be applied in place, got level %s", GetLevel())
+	}
+
+	writeEnvFile(t, envFile, "INFO", secondLog)
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if Get() != first {
+		t.Error("expected output change to rebuild the global logger in place")
+	}
+
+	writeEnvFile(t, envFile, "LOUD", secondLog)
+	if err := w.Reload(); !errors.Is(err, config.ErrInvalidValue) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+	if GetLevel() != LogLevelInfo {
+		t.Errorf("expected rejected config to keep the current level, got %s", GetLevel())
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = Get().Close()
+
+	if got := readLog(t, firstLog); strings.Count(got, "config reloaded") != 1 {
+		t.Errorf("expected the in-place reload in first.log, got:\n%s", got)
+	}
+	got := readLog(t, secondLog)
+	if !strings.Contains(got, `"message":"config reloaded"`) || !strings.Contains(got, `"message":"config rejected"`) {
+		t.Errorf("expected reload and rejection events in second.log, got:\n%s", got)
+	}
+}
+
+// fileSinksClosed reports whether all the file outputs of gen are closed.
+func fileSinksClosed(t *testing.T, gen *generation) bool {
+	t.Helper()
+	closed := true
+	for _, c := range gen.closers {
+		sink, ok := c.(*fileSink)
+		if !ok {
+			t.Fatalf("expected file outputs, got %T", c)
+		}
+		sink.mu.Lock()
+		closed = closed && sink.closed
+		sink.mu.Unlock()
+	}
+	return closed
+}
+
+// TestWatcher_ReloadKeepsDerived tests that loggers derived from the global
+// logger before a rebuild write to the new outputs.
+func TestWatcher_ReloadKeepsDerived(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	firstLog, secondLog := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	writeEnvFile(t, envFile, "INFO", firstLog)
+	initFromEnvFile(t, envFile)
+	defer func() { _ = Get().Close() }()
+
+	w, err := WatchConfigFile(envFile, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	derived := Get().With(zap.String("request_id", "r1"))
+	named := Get().Named("db")
+	derived.Info("before")
+
+	writeEnvFile(t, envFile, "INFO", secondLog)
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Close the replaced outputs, as the grace period would.
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	derived.Info("after")
+	named.Info("named after")
+
+	lines := readLines(t, secondLog)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 entries in second.log, got %v", lines)
+	}
+	if !strings.Contains(lines[1], `"message":"after"`) || !strings.Contains(lines[1], `"request_id":"r1"`) || !strings.Contains(lines[1], `"service":"watch-service"`) {
+		t.Errorf("expected the With logger to keep its fields, got %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"logger":"db"`) {
+		t.Errorf("expected the named logger to keep its name, got %s", lines[2])
+	}
+	if got := readLog(t, firstLog); strings.Contains(got, "after") {
+		t.Errorf("expected no entries after the reload in first.log, got:\n%s", got)
+	}
+}
+
+// TestWatcher_ReloadClosesReplaced tests that outputs replaced by reloads are
+// closed once superseded or after the grace period, not kept until Close.
+func TestWatcher_ReloadClosesReplaced(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	writeEnvFile(t, envFile, "INFO", filepath.Join(dir, "0.log"))
+	initFromEnvFile(t, envFile)
+	defer func() { _ = Get().Close() }()
+
+	w, err := WatchConfigFile(envFile, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	w.grace = time.Hour
+
+	const reloads = 5
+	var replaced []*generation
+	for i := 1; i <= reloads; i++ {
+		replaced = append(replaced, Get().current())
+		writeEnvFile(t, envFile, "INFO", filepath.Join(dir, strconv.Itoa(i)+".log"))
+		if err := w.Reload(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for i, gen := range replaced[:reloads-1] {
+		if !fileSinksClosed(t, gen) {
+			t.Errorf("expected outputs replaced by reload %d to be closed", i+1)
+		}
+	}
+	last := replaced[reloads-1]
+	if fileSinksClosed(t, last) {
+		t.Error("expected the last replaced outputs to stay open during the grace period")
+	}
+	if w.retired != last {
+		t.Error("expected only the last replaced outputs to be retained")
+	}
+
+	w.mu.Lock()
+	w.grace = 10 * time.Millisecond
+	w.mu.Unlock()
+	current := Get().current()
+	writeEnvFile(t, envFile, "INFO", filepath.Join(dir, "grace.log"))
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !fileSinksClosed(t, current) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the grace period to close the replaced outputs")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fileSinksClosed(t, last) {
+		t.Error("expected Close to close the replaced outputs")
+	}
+	if fileSinksClosed(t, Get().current()) {
+		t.Error("expected the current outputs to stay open")
+	}
+}
+
+// TestWatchConfigFile_MissingFile tests that a missing file is reported.
+func TestWatchConfigFile_MissingFile(t *testing.T) {
+	if _, err := WatchConfigFile(filepath.Join(t.TempDir(), "missing.env"), 0); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+// TestWatcher_ReloadFile tests reloading a YAML configuration file.
+func TestWatcher_ReloadFile(t *testing.T) {
+	resetGlobalLogger()
+	defer resetGlobalLogger()
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	write := func(level string) {
+		content := "logger:\n  level: " + level + "\n  environment: production\n  service_name: watch-service\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+	}
+	write("INFO")
+	if err := InitFromFile(path); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+
+	w, err := WatchConfigFile(path, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer w.Close()
+
+	write("ERROR")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetLevel() != LogLevelError {
+		t.Errorf("expected level from file, got %s", GetLevel())
+	}
+}